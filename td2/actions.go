@@ -0,0 +1,338 @@
+package tenderduty
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The actions behind the buttons on Slack and Discord alerts.
+const (
+	actionAck     = "ack"
+	actionSilence = "silence"
+	actionResolve = "resolve"
+)
+
+// alertActions are the buttons added to an alert, in the order they are shown.
+var alertActions = []struct{ action, label string }{
+	{actionAck, "Acknowledge"},
+	{actionSilence, "Silence 1h"},
+	{actionResolve, "Resolve"},
+}
+
+// actionSeparator separates the chain from the alert ID in a button's value.
+const actionSeparator = "|"
+
+// actionValue is what a button carries: the name of the chain in the config file and the alert ID.
+func actionValue(msg *alertMsg) string {
+	return msg.configName + actionSeparator + msg.uniqueId
+}
+
+// parseActionValue splits a button's value, buttons on messages sent by older versions only have the alert ID.
+func parseActionValue(value string) (chain, alertID string) {
+	if chain, alertID, ok := strings.Cut(value, actionSeparator); ok {
+		return chain, alertID
+	}
+	return "", value
+}
+
+// the Slack and Discord callbacks are rejected if their timestamp is older than this, to prevent replays
+const callbackMaxAge = 5 * time.Minute
+
+// alertAction applies a button click to an active alert, and returns the confirmation that is posted in the chat.
+// Every action only applies to the alert on the given chain, or on every chain when it is empty.
+func (c *Config) alertAction(action, chain, alertID, user string) (string, error) {
+	switch action {
+	case actionAck:
+		chains := alarms.acknowledge(chain, alertID, user)
+		if len(chains) == 0 {
+			return "", fmt.Errorf("%s is no longer active", alertID)
+		}
+		l(slog.LevelInfo, fmt.Sprintf("👀 %s acknowledged by %s on %s", alertID, user, strings.Join(chains, ", ")))
+		return fmt.Sprintf("👀 %s acknowledged by %s on %s", alertID, user, strings.Join(chains, ", ")), nil
+	case actionSilence:
+		s := &Silence{Chain: chain, AlertID: alertID, Duration: "1h", Comment: "silenced by " + user}
+		if err := silences.add(s); err != nil {
+			return "", err
+		}
+		l(slog.LevelInfo, fmt.Sprintf("🔕 silence %s for %s added by %s", s.ID, alertID, user))
+		return fmt.Sprintf("🔕 %s silenced by %s until %s", alertID, user, s.End.UTC().Format(time.RFC3339)), nil
+	case actionResolve:
+		if !c.resolveAlert(chain, alertID) {
+			return "", fmt.Errorf("%s is no longer active", alertID)
+		}
+		l(slog.LevelInfo, fmt.Sprintf("💜 %s resolved by %s", alertID, user))
+		if slices.Contains(reraisedKinds, kindOf(alertID)) {
+			return fmt.Sprintf("💜 %s resolved by %s, it will alert again if the problem persists", alertID, user), nil
+		}
+		return fmt.Sprintf("💜 %s resolved by %s, it will not alert again until the problem clears and comes back", alertID, user), nil
+	default:
+		return "", fmt.Errorf("unknown action %s", action)
+	}
+}
+
+// reraisedKinds are checked against their threshold every time, so an alert that is resolved by hand is raised again
+// while the problem persists. The others are raised when something changes, like the validator being jailed or a
+// node going down, and stay quiet until that happens again.
+var reraisedKinds = []alertKind{
+	kindConsecutiveBlocksMissed,
+	kindPercentageBlocksMissed,
+	kindNoRPCEndpoints,
+	kindConsecutiveEmptyBlocks,
+	kindPercentageEmptyBlocks,
+	kindMissedProposals,
+	kindUnclaimedRewards,
+	kindUnvotedGovernanceProposal,
+}
+
+// resolveAlert clears an active alert on a chain, or on every chain it is raised on when the chain is empty, the
+// resolution is sent as usual.
+func (c *Config) resolveAlert(chain, alertID string) bool {
+	type active struct {
+		chain string
+		cache alertMsgCache
+	}
+	found := make([]active, 0)
+	alarms.notifyMux.RLock()
+	for name, chainAlarms := range alarms.AllAlarms {
+		if chain != "" && name != chain {
+			continue
+		}
+		if cache, ok := chainAlarms[alertID]; ok {
+			found = append(found, active{chain: name, cache: cache})
+		}
+	}
+	alarms.notifyMux.RUnlock()
+	for _, a := range found {
+		id := alertID
+		c.alert(a.chain, a.cache.Message, a.cache.Severity, true, &id, a.cache.details(alertID))
+	}
+	return len(found) > 0
+}
+
+// callbackSecrets collects a setting from the default and the chain alert configs, the callbacks accept a click
+// signed with any of them.
+func (c *Config) callbackSecrets(setting func(*AlertConfig) string) []string {
+	secrets := make([]string, 0)
+	add := func(s string) {
+		if s != "" && !slices.Contains(secrets, s) {
+			secrets = append(secrets, s)
+		}
+	}
+	add(setting(&c.DefaultAlertConfig))
+	c.chainsMux.RLock()
+	defer c.chainsMux.RUnlock()
+	for _, cc := range c.Chains {
+		add(setting(&cc.Alerts))
+	}
+	return secrets
+}
+
+// callbackFresh checks that a callback's unix timestamp is recent.
+func callbackFresh(timestamp string) bool {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := time.Since(time.Unix(ts, 0))
+	return age < callbackMaxAge && age > -callbackMaxAge
+}
+
+// verifySlackSignature checks the X-Slack-Signature header, see https://api.slack.com/authentication/verifying-requests-from-slack
+func verifySlackSignature(header http.Header, body []byte, secrets []string) bool {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	signature := header.Get("X-Slack-Signature")
+	if !callbackFresh(timestamp) || !strings.HasPrefix(signature, "v0=") {
+		return false
+	}
+	for _, secret := range secrets {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte("v0:" + timestamp + ":"))
+		mac.Write(body)
+		if hmac.Equal([]byte(signature), []byte("v0="+hex.EncodeToString(mac.Sum(nil)))) {
+			return true
+		}
+	}
+	return false
+}
+
+type slackInteraction struct {
+	Type string `json:"type"`
+	User struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"user"`
+	Actions []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+	ResponseURL string `json:"response_url"`
+}
+
+// slackActionHandler receives the button clicks from Slack. The result is posted back to the channel through the
+// interaction's response_url.
+func slackActionHandler(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		writer.Header().Set("Allow", "POST")
+		http.Error(writer, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(request.Body, 1<<20))
+	if err != nil {
+		http.Error(writer, "could not read request", http.StatusBadRequest)
+		return
+	}
+	if !verifySlackSignature(request.Header, body, td.callbackSecrets(func(a *AlertConfig) string { return a.Slack.SigningSecret })) {
+		l(slog.LevelWarn, "🔏 rejected a slack callback with an invalid signature")
+		http.Error(writer, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(writer, "invalid form", http.StatusBadRequest)
+		return
+	}
+	interaction := &slackInteraction{}
+	if err = json.Unmarshal([]byte(form.Get("payload")), interaction); err != nil || interaction.Type != "block_actions" || len(interaction.Actions) == 0 {
+		http.Error(writer, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	user := interaction.User.Username
+	if user == "" {
+		user = interaction.User.ID
+	}
+	chain, alertID := parseActionValue(interaction.Actions[0].Value)
+	result, err := td.alertAction(interaction.Actions[0].ActionID, chain, alertID, "@"+user)
+	if err != nil {
+		result = "⚠️ " + err.Error()
+	}
+	writer.WriteHeader(http.StatusOK)
+
+	if interaction.ResponseURL != "" {
+		go func() {
+			data, _ := json.Marshal(map[string]any{"response_type": "in_channel", "replace_original": false, "text": result})
+			client := &http.Client{Timeout: 10 * time.Second}
+			resp, e := client.Post(interaction.ResponseURL, "application/json", bytes.NewReader(data))
+			if e != nil {
+				l(slog.LevelWarn, "slack response:", e)
+				return
+			}
+			_ = resp.Body.Close()
+		}()
+	}
+}
+
+// discordPublicKey decodes a Discord application's hex encoded public key.
+func discordPublicKey(key string) (ed25519.PublicKey, error) {
+	b, err := hex.DecodeString(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != ed25519.PublicKeySize {
+		return nil, errors.New("an ed25519 public key is 32 bytes")
+	}
+	return b, nil
+}
+
+// verifyDiscordSignature checks the X-Signature-Ed25519 header, see
+// https://discord.com/developers/docs/interactions/overview#setting-up-an-endpoint-validating-security-request-headers
+func verifyDiscordSignature(header http.Header, body []byte, keys []string) bool {
+	timestamp := header.Get("X-Signature-Timestamp")
+	signature, err := hex.DecodeString(header.Get("X-Signature-Ed25519"))
+	if err != nil || !callbackFresh(timestamp) {
+		return false
+	}
+	for _, key := range keys {
+		pub, e := discordPublicKey(key)
+		if e == nil && ed25519.Verify(pub, append([]byte(timestamp), body...), signature) {
+			return true
+		}
+	}
+	return false
+}
+
+// Discord interaction and response types
+const (
+	discordPing                     = 1
+	discordMessageComponent         = 3
+	discordPong                     = 1
+	discordChannelMessageWithSource = 4
+)
+
+type discordUser struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+type discordInteraction struct {
+	Type int `json:"type"`
+	Data struct {
+		CustomID string `json:"custom_id"`
+	} `json:"data"`
+	// Member is set for clicks in a server, User in a direct message
+	Member *struct {
+		User discordUser `json:"user"`
+	} `json:"member"`
+	User *discordUser `json:"user"`
+}
+
+// discordActionHandler is the interactions endpoint of the Discord application, the result of a click is the reply.
+func discordActionHandler(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		writer.Header().Set("Allow", "POST")
+		http.Error(writer, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(request.Body, 1<<20))
+	if err != nil {
+		http.Error(writer, "could not read request", http.StatusBadRequest)
+		return
+	}
+	// Discord checks that requests with a bad signature are rejected before it accepts the endpoint
+	if !verifyDiscordSignature(request.Header, body, td.callbackSecrets(func(a *AlertConfig) string { return a.Discord.PublicKey })) {
+		l(slog.LevelWarn, "🔏 rejected a discord callback with an invalid signature")
+		http.Error(writer, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	interaction := &discordInteraction{}
+	if err = json.Unmarshal(body, interaction); err != nil {
+		http.Error(writer, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	switch interaction.Type {
+	case discordPing:
+		writeJSON(writer, http.StatusOK, map[string]int{"type": discordPong})
+	case discordMessageComponent:
+		user := "unknown"
+		if interaction.Member != nil {
+			user = interaction.Member.User.Username
+		} else if interaction.User != nil {
+			user = interaction.User.Username
+		}
+		action, value, _ := strings.Cut(interaction.Data.CustomID, ":")
+		chain, alertID := parseActionValue(value)
+		result, e := td.alertAction(action, chain, alertID, "@"+user)
+		if e != nil {
+			result = "⚠️ " + e.Error()
+		}
+		writeJSON(writer, http.StatusOK, map[string]any{
+			"type": discordChannelMessageWithSource,
+			"data": map[string]string{"content": result},
+		})
+	default:
+		http.Error(writer, "unsupported interaction", http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,232 @@
+package tenderduty
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupActionsTest(t *testing.T) string {
+	originalTd, originalSilences := td, silences
+	td = createTestConfig()
+	td.ctx, td.cancel = context.WithCancel(context.Background())
+	id := "ValidatorInactive_testval123"
+	newTestAlarmCache(t).AllAlarms = map[string]map[string]alertMsgCache{
+		"test-chain": {id: {Message: "testval is jailed", Severity: "critical", SentTime: time.Now()}},
+		// the same validator address on another chain
+		"other-chain": {id: {Message: "testval is jailed", Severity: "critical", SentTime: time.Now()}},
+	}
+	silences = newSilenceStore()
+	t.Cleanup(func() {
+		td.cancel()
+		td, silences = originalTd, originalSilences
+	})
+	return id
+}
+
+func TestAlertAction(t *testing.T) {
+	id := setupActionsTest(t)
+
+	if _, err := td.alertAction(actionAck, "test-chain", "Unknown_id", "@ops"); err == nil {
+		t.Error("acknowledging an inactive alert should fail")
+	}
+	if _, err := td.alertAction("reboot", "test-chain", id, "@ops"); err == nil {
+		t.Error("unknown action should fail")
+	}
+
+	if _, err := td.alertAction(actionAck, "test-chain", id, "@ops"); err != nil || alarms.AllAlarms["test-chain"][id].AckedBy != "@ops" {
+		t.Errorf("alert should be acknowledged, got %v", err)
+	}
+	if alarms.AllAlarms["other-chain"][id].AckedBy != "" {
+		t.Error("the acknowledgement should only apply to the alert on its chain")
+	}
+
+	if _, err := td.alertAction(actionSilence, "test-chain", id, "@ops"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	s := silences.match("test-chain", "test-chain-1", id, "testval123")
+	if s == nil || time.Until(s.End) > time.Hour || time.Until(s.End) < 59*time.Minute {
+		t.Errorf("alert should be silenced for an hour, got %+v", s)
+	}
+	if silences.match("other-chain", "other-chain-1", id, "testval123") != nil || silences.match("test-chain", "test-chain-1", id+"4", "testval1234") != nil {
+		t.Error("the silence should only apply to the alert on its chain")
+	}
+
+	reply, err := td.alertAction(actionResolve, "test-chain", id, "@ops")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	// the validator is only alerted about again when it is jailed again
+	if !strings.Contains(reply, "will not alert again until the problem clears") {
+		t.Errorf("unexpected reply %q", reply)
+	}
+	msg := <-td.alertChan
+	if !msg.resolved || msg.uniqueId != id || msg.configName != "test-chain" {
+		t.Errorf("expected the resolution to be sent, got %+v", msg)
+	}
+	if alarms.exist("test-chain", id) {
+		t.Error("resolved alert should no longer be active")
+	}
+	if !alarms.exist("other-chain", id) || len(td.alertChan) != 0 {
+		t.Error("the alert on the other chain should not be resolved")
+	}
+
+	missed := "ConsecutiveBlocksMissed_testval123"
+	alarms.AllAlarms["test-chain"][missed] = alertMsgCache{Message: "testval has missed 10 blocks", Severity: "critical", SentTime: time.Now()}
+	if reply, err = td.alertAction(actionResolve, "test-chain", missed, "@ops"); err != nil || !strings.Contains(reply, "will alert again if the problem persists") {
+		t.Errorf("unexpected reply %q %v", reply, err)
+	}
+	<-td.alertChan
+}
+
+func TestBuildMessagesWithButtons(t *testing.T) {
+	msg := &alertMsg{
+		chain:       "test-chain",
+		message:     "testval is jailed",
+		configName:  "test-chain",
+		uniqueId:    "ValidatorInactive_testval123",
+		alertConfig: &AlertConfig{Slack: SlackConfig{Interactive: boolPtr(true)}, Discord: DiscordConfig{Interactive: boolPtr(true)}},
+	}
+
+	sm := buildSlackMessage(msg)
+	if len(sm.Blocks) != 2 || len(sm.Blocks[1].Elements) != 3 {
+		t.Fatalf("expected a section and three buttons, got %+v", sm.Blocks)
+	}
+	if b := sm.Blocks[1].Elements[2]; b.ActionID != actionResolve || b.Value != "test-chain|"+msg.uniqueId || b.Text.Text != "Resolve" {
+		t.Errorf("unexpected button %+v", b)
+	}
+
+	dm := buildDiscordMessage(msg)
+	if len(dm.Components) != 1 || len(dm.Components[0].Components) != 3 {
+		t.Fatalf("expected an action row with three buttons, got %+v", dm.Components)
+	}
+	if b := dm.Components[0].Components[1]; b.CustomID != "silence:test-chain|"+msg.uniqueId || b.Label != "Silence 1h" {
+		t.Errorf("unexpected button %+v", b)
+	}
+
+	msg.uniqueId = strings.Repeat("x", maxDiscordCustomID)
+	if dm = buildDiscordMessage(msg); len(dm.Components) != 0 {
+		t.Error("buttons should be left out when the alert ID is too long")
+	}
+
+	msg.resolved = true
+	if len(buildSlackMessage(msg).Blocks) != 0 || len(buildDiscordMessage(msg).Components) != 0 {
+		t.Error("resolutions should not have buttons")
+	}
+}
+
+func signSlack(req *http.Request, secret, body string, ts time.Time) {
+	timestamp := strconv.FormatInt(ts.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body))
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+}
+
+func TestSlackActionHandler(t *testing.T) {
+	id := setupActionsTest(t)
+	td.DefaultAlertConfig.Slack.SigningSecret = "default-secret"
+	td.Chains["test-chain"].Alerts.Slack.SigningSecret = "chain-secret"
+
+	responses := make(chan string, 1)
+	responder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		responses <- string(b)
+	}))
+	defer responder.Close()
+
+	payload, _ := json.Marshal(map[string]any{
+		"type":         "block_actions",
+		"user":         map[string]string{"id": "U123", "username": "ops"},
+		"actions":      []map[string]string{{"action_id": actionAck, "value": "test-chain|" + id}},
+		"response_url": responder.URL,
+	})
+	body := url.Values{"payload": {string(payload)}}.Encode()
+	send := func(secret string, ts time.Time) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/actions/slack", strings.NewReader(body))
+		signSlack(req, secret, body, ts)
+		rec := httptest.NewRecorder()
+		slackActionHandler(rec, req)
+		return rec.Code
+	}
+
+	if code := send("wrong-secret", time.Now()); code != http.StatusUnauthorized {
+		t.Errorf("bad signature should be rejected, got %d", code)
+	}
+	if code := send("chain-secret", time.Now().Add(-10*time.Minute)); code != http.StatusUnauthorized {
+		t.Errorf("old request should be rejected, got %d", code)
+	}
+	if code := send("chain-secret", time.Now()); code != http.StatusOK {
+		t.Fatalf("signed request should be accepted, got %d", code)
+	}
+	if alarms.AllAlarms["test-chain"][id].AckedBy != "@ops" {
+		t.Error("alert should be acknowledged by @ops")
+	}
+	select {
+	case r := <-responses:
+		if !strings.Contains(r, "acknowledged by @ops") || !strings.Contains(r, `"response_type":"in_channel"`) {
+			t.Errorf("unexpected response %s", r)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected the result to be posted to the response_url")
+	}
+}
+
+func TestDiscordActionHandler(t *testing.T) {
+	id := setupActionsTest(t)
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	td.DefaultAlertConfig.Discord.PublicKey = hex.EncodeToString(pub)
+
+	send := func(body string, key ed25519.PrivateKey) *httptest.ResponseRecorder {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req := httptest.NewRequest(http.MethodPost, "/api/actions/discord", strings.NewReader(body))
+		req.Header.Set("X-Signature-Timestamp", timestamp)
+		req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(ed25519.Sign(key, []byte(timestamp+body))))
+		rec := httptest.NewRecorder()
+		discordActionHandler(rec, req)
+		return rec
+	}
+
+	_, other, _ := ed25519.GenerateKey(nil)
+	if rec := send(`{"type":1}`, other); rec.Code != http.StatusUnauthorized {
+		t.Errorf("bad signature should be rejected, got %d", rec.Code)
+	}
+	if rec := send(`{"type":1}`, priv); rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != `{"type":1}` {
+		t.Errorf("ping should be answered, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec := send(`{"type":3,"data":{"custom_id":"ack:`+id+`"},"member":{"user":{"id":"1","username":"ops"}}}`, priv)
+	reply := struct {
+		Type int `json:"type"`
+		Data struct {
+			Content string `json:"content"`
+		} `json:"data"`
+	}{}
+	if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &reply) != nil {
+		t.Fatalf("unexpected response %d %s", rec.Code, rec.Body.String())
+	}
+	if reply.Type != discordChannelMessageWithSource || !strings.Contains(reply.Data.Content, "acknowledged by @ops") {
+		t.Errorf("unexpected reply %+v", reply)
+	}
+	if alarms.AllAlarms["test-chain"][id].AckedBy != "@ops" {
+		t.Error("alert should be acknowledged by @ops")
+	}
+
+	if _, err = discordPublicKey("abcd"); err == nil {
+		t.Error("short public key should be rejected")
+	}
+}
@@ -1,83 +1,215 @@
 package tenderduty
 
 import (
-	"bytes"
-	"context"
-	"encoding/json"
 	"fmt"
 	"log/slog"
 	"math"
-	"net/http"
 	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/PagerDuty/go-pagerduty"
 	github_com_cosmos_cosmos_sdk_types "github.com/cosmos/cosmos-sdk/types"
 	"github.com/firstset/tenderduty/v2/td2/utils"
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 type alertMsg struct {
-	pd   bool
-	disc bool
-	tg   bool
-	slk  bool
-	wh   bool
+	// notifiers are the destinations enabled for the chain when the alert was raised
+	notifiers []Notifier
 
 	severity       string
 	resolved       bool
 	chain          string
+	configName     string
+	chainId        string
 	chainName      string
 	valoperAddress string
 	valconsAddress string
+	moniker        string
+	chainLabels    map[string]string
 	message        string
 	uniqueId       string
-	key            string
+	alertDetails
+	// inhibited is set on a resolution when the alert was held back by an inhibit rule and never sent
+	inhibited bool
+	// eventID is the alert history event that deliveries are recorded on
+	eventID string
+	// flap is set on the flapping notice and the final state of a flapping alert
+	flap flapPhase
 
-	tgChannel  string
-	tgKey      string
-	tgMentions string
+	alertConfig *AlertConfig
+}
 
-	discHook     string
-	discMentions string
+// alertKind is what an alert is about, it is also the first part of the alert's unique ID.
+type alertKind string
 
-	slkHook     string
-	slkMentions string
+const (
+	kindConsecutiveBlocksMissed   alertKind = "ConsecutiveBlocksMissed"
+	kindPercentageBlocksMissed    alertKind = "PercentageBlocksMissed"
+	kindNoRPCEndpoints            alertKind = "NoRPCEndpoints"
+	kindChainStalled              alertKind = "ChainStalled"
+	kindValidatorInactive         alertKind = "ValidatorInactive"
+	kindConsecutiveEmptyBlocks    alertKind = "ConsecutiveEmptyBlocks"
+	kindPercentageEmptyBlocks     alertKind = "PercentageEmptyBlocks"
+	kindRPCNodeDown               alertKind = "RPCNodeDown"
+	kindStakeChange               alertKind = "StakeChange"
+	kindUnclaimedRewards          alertKind = "UnclaimedRewards"
+	kindUnvotedGovernanceProposal alertKind = "UnvotedGovernanceProposal"
+	kindDoubleSign                alertKind = "DoubleSign"
+	kindEvidence                  alertKind = "Evidence"
+	kindMissedProposals           alertKind = "MissedProposals"
+)
 
-	whURL string
+// alertKinds are all the kinds of alert, used to check the config.
+var alertKinds = []alertKind{
+	kindConsecutiveBlocksMissed, kindPercentageBlocksMissed, kindNoRPCEndpoints, kindChainStalled, kindValidatorInactive,
+	kindConsecutiveEmptyBlocks, kindPercentageEmptyBlocks, kindRPCNodeDown, kindStakeChange, kindUnclaimedRewards,
+	kindUnvotedGovernanceProposal, kindDoubleSign, kindEvidence, kindMissedProposals,
+}
 
-	alertConfig *AlertConfig
+// kindOf returns the kind from an alert's unique ID, for alerts that were saved without one.
+func kindOf(id string) alertKind {
+	kind, _, _ := strings.Cut(id, "_")
+	return alertKind(kind)
 }
 
-type notifyDest uint8
+// alertDetails are the structured fields of an alert. The labels tell apart alerts of the same kind, for example the
+// RPC node or the proposal. The value and threshold are what raised the alert, such as the number of missed blocks and
+// the configured limit, they are zero for alerts that are not numeric.
+type alertDetails struct {
+	kind      alertKind
+	labels    map[string]string
+	value     float64
+	threshold float64
+}
 
-const (
-	pd notifyDest = iota
-	tg
-	di
-	slk
-	wh
-)
+// numeric reports whether the alert has a value and threshold.
+func (d alertDetails) numeric() bool {
+	return d.value != 0 || d.threshold != 0
+}
+
+// notifies reports whether the named destination will be sent this alert.
+func (a *alertMsg) notifies(name string) bool {
+	for _, n := range a.notifiers {
+		if n.Name() == name {
+			return true
+		}
+	}
+	return false
+}
 
 type alertMsgCache struct {
 	Message  string    `json:"message"`
+	Severity string    `json:"severity,omitempty"`
 	SentTime time.Time `json:"sent_time"`
+	// Repeats counts the reminders sent since the alert was first delivered
+	Repeats int `json:"repeats,omitempty"`
+	// AckedBy is who acknowledged the alert, acknowledged alerts are not repeated or escalated until they resolve
+	AckedBy string `json:"acked_by,omitempty"`
+	// the alert's details are kept so that reminders and escalations have them too
+	Kind      alertKind         `json:"kind,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Value     float64           `json:"value,omitempty"`
+	Threshold float64           `json:"threshold,omitempty"`
+	// EventID is the alert history event of when the alert fired
+	EventID string `json:"event_id,omitempty"`
+}
+
+// details returns the details of a cached alert, the kind is taken from the ID for state saved by older releases.
+func (c alertMsgCache) details(alertID string) alertDetails {
+	d := alertDetails{kind: c.Kind, labels: c.Labels, value: c.Value, threshold: c.Threshold}
+	if d.kind == "" {
+		d.kind = kindOf(alertID)
+	}
+	return d
 }
 
 type alarmCache struct {
 	// the key of an alertMsgCache is the unique ID of the alert
 	// we use the following convention for the unique ID: <alert_name>_<val_address>_<other_info>
-	SentPdAlarms   map[string]alertMsgCache            `json:"sent_pd_alarms"`
-	SentTgAlarms   map[string]alertMsgCache            `json:"sent_tg_alarms"`
-	SentDiAlarms   map[string]alertMsgCache            `json:"sent_di_alarms"`
-	SentSlkAlarms  map[string]alertMsgCache            `json:"sent_slk_alarms"`
-	SentWHAlarms   map[string]alertMsgCache            `json:"sent_wh_alarms"`
-	AllAlarms      map[string]map[string]alertMsgCache `json:"sent_all_alarms"`
-	flappingAlarms map[string]map[string]alertMsgCache
-	notifyMux      sync.RWMutex
+	// Sent is keyed by the Notifier's name, and tracks what was delivered to each destination.
+	Sent      map[string]map[string]alertMsgCache `json:"sent_alarms"`
+	AllAlarms map[string]map[string]alertMsgCache `json:"sent_all_alarms"`
+	// flaps tracks the state changes of alerts for the flap detection, keyed by chain and alert ID
+	flaps map[string]map[string]*flapState
+	// inhibited holds alerts that were held back by an inhibit rule, keyed by chain and alert ID
+	inhibited map[string]map[string]*alertMsg
+	// silenced holds alerts that were held back by a silence, keyed by chain and alert ID
+	silenced map[string]map[string]*alertMsg
+	// pending holds alerts waiting for their `for` or `resolve_for` duration, keyed by chain and alert ID
+	pending map[string]map[string]*pendingAlert
+	// totals counts the alerts on each chain since tenderduty started, for the digests
+	totals    map[string]*alertTotal
+	notifyMux sync.RWMutex
+}
+
+// alertTotal is how many alerts fired on a chain, and for how long the alerts that resolved were active.
+type alertTotal struct {
+	fired           int
+	resolvedSeconds float64
+}
+
+// total returns the counters of a chain, the caller must hold the lock.
+func (a *alarmCache) total(chain string) *alertTotal {
+	if a.totals == nil {
+		a.totals = make(map[string]*alertTotal)
+	}
+	if a.totals[chain] == nil {
+		a.totals[chain] = &alertTotal{}
+	}
+	return a.totals[chain]
+}
+
+// alertTotals returns how many alerts fired on a chain since tenderduty started, how many are active now, and the
+// time alerts were active in total including the active ones up to now. The digests report the difference between
+// two calls.
+func (a *alarmCache) alertTotals(chain string, now time.Time) (fired, active int, seconds float64) {
+	a.notifyMux.RLock()
+	defer a.notifyMux.RUnlock()
+	if t := a.totals[chain]; t != nil {
+		fired, seconds = t.fired, t.resolvedSeconds
+	}
+	for _, cache := range a.AllAlarms[chain] {
+		active += 1
+		if !cache.SentTime.IsZero() {
+			seconds += now.Sub(cache.SentTime).Seconds()
+		}
+	}
+	return
+}
+
+// legacyAlarmCache is the layout used by older releases which had a hard-coded map per destination. It is only
+// read when restoring state so that an upgrade does not re-send active alarms.
+type legacyAlarmCache struct {
+	SentPdAlarms  map[string]alertMsgCache `json:"sent_pd_alarms"`
+	SentTgAlarms  map[string]alertMsgCache `json:"sent_tg_alarms"`
+	SentDiAlarms  map[string]alertMsgCache `json:"sent_di_alarms"`
+	SentSlkAlarms map[string]alertMsgCache `json:"sent_slk_alarms"`
+	SentWHAlarms  map[string]alertMsgCache `json:"sent_wh_alarms"`
+}
+
+// sent returns the legacy maps keyed by the name of the Notifier that replaced them.
+func (la *legacyAlarmCache) sent() map[string]map[string]alertMsgCache {
+	return map[string]map[string]alertMsgCache{
+		"pagerduty": la.SentPdAlarms,
+		"telegram":  la.SentTgAlarms,
+		"discord":   la.SentDiAlarms,
+		"slack":     la.SentSlkAlarms,
+		"webhook":   la.SentWHAlarms,
+	}
+}
+
+// sentFor returns the sent-state for a destination, creating it if needed. The caller must hold notifyMux.
+func (a *alarmCache) sentFor(name string) map[string]alertMsgCache {
+	if a.Sent == nil {
+		a.Sent = make(map[string]map[string]alertMsgCache)
+	}
+	if a.Sent[name] == nil {
+		a.Sent[name] = make(map[string]alertMsgCache)
+	}
+	return a.Sent[name]
 }
 
 func (a *alarmCache) clearNoBlocks(cc *ChainConfig) {
@@ -93,6 +225,7 @@ func (a *alarmCache) clearNoBlocks(cc *ChainConfig) {
 				"critical",
 				true,
 				&alertID,
+				alertDetails{kind: kindChainStalled, value: time.Since(cc.lastBlockTime).Minutes(), threshold: float64(intVal(cc.Alerts.Stalled))},
 			)
 		}
 	}
@@ -114,6 +247,27 @@ func (a *alarmCache) clearAll(chain string) {
 	a.notifyMux.Lock()
 	defer a.notifyMux.Unlock()
 	a.AllAlarms[chain] = make(map[string]alertMsgCache)
+	delete(a.pending, chain)
+}
+
+// expire forgets an active alert without sending a resolution, for alerts about a single event that has no condition
+// to clear them. It returns the alerts that were inhibited by it and need to be delivered now.
+func (a *alarmCache) expire(chain, alertID string) []*alertMsg {
+	a.notifyMux.Lock()
+	defer a.notifyMux.Unlock()
+	cache, ok := a.AllAlarms[chain][alertID]
+	if !ok {
+		return nil
+	}
+	if !cache.SentTime.IsZero() {
+		a.total(chain).resolvedSeconds += time.Since(cache.SentTime).Seconds()
+	}
+	delete(a.AllAlarms[chain], alertID)
+	delete(a.inhibited[chain], alertID)
+	for _, sent := range a.Sent {
+		delete(sent, alertID)
+	}
+	return a.releaseInhibited(chain)
 }
 
 func (a *alarmCache) exist(chain string, alertID string) bool {
@@ -127,407 +281,176 @@ func (a *alarmCache) exist(chain string, alertID string) bool {
 	return ok
 }
 
+// acknowledge marks the active alerts with the given ID on a chain, or on every chain when it is empty, as
+// acknowledged, and returns the chains they are on.
+func (a *alarmCache) acknowledge(chain, alertID, by string) []string {
+	a.notifyMux.Lock()
+	defer a.notifyMux.Unlock()
+	chains := make([]string, 0)
+	for name, chainAlarms := range a.AllAlarms {
+		if chain != "" && name != chain {
+			continue
+		}
+		if cache, ok := chainAlarms[alertID]; ok {
+			cache.AckedBy = by
+			chainAlarms[alertID] = cache
+			chains = append(chains, name)
+		}
+	}
+	sort.Strings(chains)
+	return chains
+}
+
 // alarms is used to prevent double notifications. TODO: save on exit / load on start
 var alarms = &alarmCache{
-	SentPdAlarms:   make(map[string]alertMsgCache),
-	SentTgAlarms:   make(map[string]alertMsgCache),
-	SentDiAlarms:   make(map[string]alertMsgCache),
-	SentSlkAlarms:  make(map[string]alertMsgCache),
-	SentWHAlarms:   make(map[string]alertMsgCache),
-	AllAlarms:      make(map[string]map[string]alertMsgCache),
-	flappingAlarms: make(map[string]map[string]alertMsgCache),
-	notifyMux:      sync.RWMutex{},
+	Sent:      make(map[string]map[string]alertMsgCache),
+	AllAlarms: make(map[string]map[string]alertMsgCache),
+	flaps:     make(map[string]map[string]*flapState),
+	notifyMux: sync.RWMutex{},
 }
 
-func shouldNotify(msg *alertMsg, dest notifyDest) bool {
+// shouldNotify decides if an alert is sent to a destination. It does not change the sent-state, that is done by
+// recordSent after the delivery succeeded.
+func shouldNotify(msg *alertMsg, dest Notifier) bool {
 	alarms.notifyMux.Lock()
 	defer alarms.notifyMux.Unlock()
-	var whichMap map[string]alertMsgCache
-	var service string
-	switch dest {
-	case pd:
-		if !slices.Contains(SeverityThresholdToSeverities(msg.alertConfig.Pagerduty.SeverityThreshold), msg.severity) {
-			return false
-		}
-		whichMap = alarms.SentPdAlarms
-		service = "PagerDuty"
-	case tg:
-		if !slices.Contains(SeverityThresholdToSeverities(msg.alertConfig.Telegram.SeverityThreshold), msg.severity) {
-			return false
-		}
-		whichMap = alarms.SentTgAlarms
-		service = "Telegram"
-	case di:
-		if !slices.Contains(SeverityThresholdToSeverities(msg.alertConfig.Discord.SeverityThreshold), msg.severity) {
-			return false
-		}
-		whichMap = alarms.SentDiAlarms
-		service = "Discord"
-	case slk:
-		if !slices.Contains(SeverityThresholdToSeverities(msg.alertConfig.Slack.SeverityThreshold), msg.severity) {
+	if !routed(msg, dest) {
+		return false
+	}
+	whichMap := alarms.sentFor(dest.Name())
+	service := dest.Name()
+
+	// a delivery for this alert is still in the outbox, let it finish or be retried
+	if outbox.supersede(msg, service) {
+		return false
+	}
+
+	// silenced alerts are still tracked in AllAlarms, but not sent. Resolutions for alerts that were delivered before
+	// the silence started still go out so that incidents are not left open.
+	if !msg.resolved {
+		if s := msg.silenced(); s != nil {
+			l(slog.LevelInfo, fmt.Sprintf("🔕 Silenced     alarm on %s (%s) - not notifying %s, silence %s", msg.chain, msg.message, service, s.ID))
+			alarms.silence(msg)
 			return false
 		}
-		whichMap = alarms.SentSlkAlarms
-		service = "Slack"
-	case wh:
-		if !slices.Contains(SeverityThresholdToSeverities(msg.alertConfig.Webhook.SeverityThreshold), msg.severity) {
+		if parent := alarms.inhibitedBy(msg.configName, msg.uniqueId); parent != "" {
+			l(slog.LevelInfo, fmt.Sprintf("🔇 Inhibited    alarm on %s (%s) by %s - not notifying %s", msg.chain, msg.message, parent, service))
+			alarms.inhibit(msg)
 			return false
 		}
-		whichMap = alarms.SentWHAlarms
-		service = "Webhook"
 	}
 
 	switch {
 	case !whichMap[msg.uniqueId].SentTime.IsZero() && !msg.resolved:
-		// TODO: this is a temporary solution for sending proposal reminders, ideally we should make this feature more general and configurable
-		// Check if this is a proposal alert that should be re-sent
-		if strings.HasPrefix(msg.uniqueId, "UnvotedGovernanceProposal") {
-			// Check if it has been 6 hours since the last (re-)send
-			if whichMap[msg.uniqueId].SentTime.Before(time.Now().Add(-1 * time.Duration(td.GovernanceAlertsReminderInterval) * time.Hour)) {
-				l(slog.LevelInfo, fmt.Sprintf("🔄 RE-SENDING ALERT on %s (%s) - notifying %s", msg.chain, msg.message, service))
-				cache := alertMsgCache{
-					Message:  msg.message,
-					SentTime: time.Now(),
-				}
-				whichMap[msg.uniqueId] = cache
-				return true
-			}
+		// already sent, only send it again for the flap detection, or if a repeat rule says a reminder is due
+		if msg.flap != flapNone {
+			l(slog.LevelInfo, fmt.Sprintf("🔁 FLAPPING     alarm on %s (%s) - notifying %s", msg.chain, msg.message, service))
+			return true
 		}
-		return false
+		prev := whichMap[msg.uniqueId]
+		rule := repeatRuleFor(msg.uniqueId, service)
+		if rule == nil || !rule.due(prev) {
+			return false
+		}
+		l(slog.LevelInfo, fmt.Sprintf("🔄 RE-SENDING ALERT on %s (%s) - notifying %s", msg.chain, msg.message, service))
+		return true
 	case !whichMap[msg.uniqueId].SentTime.IsZero() && msg.resolved:
 		// alarm is cleared
-		delete(whichMap, msg.uniqueId)
 		l(slog.LevelInfo, fmt.Sprintf("💜 Resolved     alarm on %s (%s) - notifying %s", msg.chain, msg.message, service))
 		return true
+	case msg.resolved && msg.inhibited:
+		// the alert was never sent because it was inhibited, so there is nothing to resolve
+		l(slog.LevelInfo, fmt.Sprintf("🔇 Not clearing inhibited alarm on %s (%s) - %s was not notified", msg.chain, msg.message, service))
+		return false
 	case msg.resolved:
 		// it looks like we got a duplicate resolution or suppressed it. Note it and move on:
 		l(slog.LevelWarn, fmt.Sprintf("😕 Not clearing alarm on %s (%s) - no corresponding alert %s", msg.chain, msg.message, service))
 		return false
 	}
 
-	// check if the alarm is flapping, if we sent the same alert in the last five minutes, show a warning but don't alert
-	if alarms.flappingAlarms[msg.chain] == nil {
-		alarms.flappingAlarms[msg.chain] = make(map[string]alertMsgCache)
-	}
-
-	// for pagerduty we perform some basic flap detection
-	if dest == pd && msg.pd && alarms.flappingAlarms[msg.chain][msg.uniqueId].SentTime.After(time.Now().Add(-5*time.Minute)) {
-		l(slog.LevelWarn, "🛑 flapping detected - suppressing pagerduty notification:", msg.chain, msg.message)
-		return false
-	} else if dest == pd && msg.pd {
-		cache := alertMsgCache{
-			Message:  msg.message,
-			SentTime: time.Now(),
-		}
-		alarms.flappingAlarms[msg.chain][msg.uniqueId] = cache
-	}
-
 	l(slog.LevelInfo, fmt.Sprintf("🚨 ALERT        new alarm on %s (%s) - notifying %s", msg.chain, msg.message, service))
-	cache := alertMsgCache{
-		Message:  msg.message,
-		SentTime: time.Now(),
-	}
-	whichMap[msg.uniqueId] = cache
 	return true
 }
 
-func notifySlack(msg *alertMsg) (err error) {
-	if !msg.slk {
-		return
-	}
-	data, err := json.Marshal(buildSlackMessage(msg))
-	if err != nil {
-		return
-	}
-
-	req, err := http.NewRequest("POST", msg.slkHook, bytes.NewBuffer(data))
-	if err != nil {
-		return
-	}
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return
-	}
-	_ = resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("could not notify slack for %s got %d response", msg.chain, resp.StatusCode)
-	}
-
-	return
-}
-
-type SlackMessage struct {
-	Text        string       `json:"text"`
-	Attachments []Attachment `json:"attachments"`
-}
-
-type Attachment struct {
-	Text      string `json:"text"`
-	Color     string `json:"color"`
-	Title     string `json:"title"`
-	TitleLink string `json:"title_link"`
-}
-
-func buildSlackMessage(msg *alertMsg) *SlackMessage {
-	prefix := "🚨 ALERT: "
-	color := "danger"
-	if msg.resolved {
-		msg.message = "OK: " + msg.message
-		prefix = "💜 Resolved: "
-		color = "good"
-	}
-	return &SlackMessage{
-		Text: msg.message,
-		Attachments: []Attachment{
-			{
-				Title: fmt.Sprintf("TenderDuty %s %s %s", prefix, msg.chain, msg.slkMentions),
-				Color: color,
-			},
-		},
-	}
-}
-
-func notifyDiscord(msg *alertMsg) (err error) {
-	if !msg.disc {
-		return nil
-	}
-	if !shouldNotify(msg, di) {
-		return nil
-	}
-	discPost := buildDiscordMessage(msg)
-	client := &http.Client{}
-	data, err := json.MarshalIndent(discPost, "", "  ")
-	if err != nil {
-		l(slog.LevelWarn, "⚠️ Could not notify discord!", err)
-		return err
-	}
-
-	req, err := http.NewRequest("POST", msg.discHook, bytes.NewBuffer(data))
-	if err != nil {
-		l(slog.LevelWarn, "⚠️ Could not notify discord!", err)
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		l(slog.LevelWarn, "⚠️ Could not notify discord!", err)
-		return err
-	}
-	_ = resp.Body.Close()
-
-	if resp.StatusCode != 204 {
-		slog.Warn("discord webhook returned non-success response", "status", resp.StatusCode)
-		l(slog.LevelWarn, "⚠️ Could not notify discord! Returned", resp.StatusCode)
-		return err
-	}
-	return nil
-}
-
-type DiscordMessage struct {
-	Username  string         `json:"username,omitempty"`
-	AvatarUrl string         `json:"avatar_url,omitempty"`
-	Content   string         `json:"content"`
-	Embeds    []DiscordEmbed `json:"embeds,omitempty"`
-}
-
-type DiscordEmbed struct {
-	Title       string `json:"title,omitempty"`
-	Url         string `json:"url,omitempty"`
-	Description string `json:"description"`
-	Color       uint   `json:"color"`
-}
-
-func buildDiscordMessage(msg *alertMsg) *DiscordMessage {
-	prefix := "🚨 ALERT: "
-	if msg.resolved {
-		prefix = "💜 Resolved: "
-	}
-	return &DiscordMessage{
-		Username: "Tenderduty",
-		Content:  prefix + msg.chain,
-		Embeds: []DiscordEmbed{{
-			Description: msg.message,
-		}},
-	}
-}
-
-func notifyTg(msg *alertMsg) (err error) {
-	if !msg.tg {
-		return nil
-	}
-	if !shouldNotify(msg, tg) {
-		return nil
-	}
-	bot, err := tgbotapi.NewBotAPI(msg.tgKey)
-	if err != nil {
-		l(slog.LevelWarn, "notify telegram:", err)
-		return
-	}
-
-	prefix := "🚨 ALERT"
+// recordSent updates the sent-state once a destination has accepted a notification. Sending an alert that was
+// already sent counts as a reminder.
+func (a *alarmCache) recordSent(msg *alertMsg, dest Notifier) {
+	a.notifyMux.Lock()
+	defer a.notifyMux.Unlock()
+	whichMap := a.sentFor(dest.Name())
 	if msg.resolved {
-		prefix = "💜 Resolved"
-	}
-
-	mc := tgbotapi.NewMessageToChannel(msg.tgChannel, fmt.Sprintf("%s: %s: %s", msg.chain, prefix, msg.message))
-	_, err = bot.Send(mc)
-	if err != nil {
-		l(slog.LevelWarn, "telegram send:", err)
-	}
-	return err
-}
-
-func notifyPagerduty(msg *alertMsg) (err error) {
-	if !msg.pd {
-		return nil
-	}
-	if !shouldNotify(msg, pd) {
-		return nil
-	}
-	// key from the example, don't spam their api
-	if msg.key == "aaaaaaaaaaaabbbbbbbbbbbbbcccccccccccc" {
-		l(slog.LevelWarn, "invalid pagerduty key")
+		delete(whichMap, msg.uniqueId)
 		return
 	}
-	action := "trigger"
-	if msg.resolved {
-		action = "resolve"
-	}
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-	_, err = pagerduty.ManageEventWithContext(ctx, pagerduty.V2Event{
-		RoutingKey: msg.key,
-		Action:     action,
-		DedupKey:   msg.uniqueId,
-		Payload: &pagerduty.V2Payload{
-			Summary:  msg.message,
-			Source:   msg.uniqueId,
-			Severity: msg.severity,
-		},
-	})
-	return
-}
-
-// WebhookPayload represents the payload sent to a generic webhook endpoint
-// The structure is inspired by Grafana's webhook contact point format
-type WebhookPayload struct {
-	Status   string         `json:"status"`
-	Alerts   []WebhookAlert `json:"alerts"`
-	Version  string         `json:"version"`
-	GroupKey string         `json:"groupKey"`
-}
-
-// WebhookAlert represents a single alert in the webhook payload
-type WebhookAlert struct {
-	Status       string            `json:"status"`
-	Labels       map[string]string `json:"labels"`
-	Annotations  map[string]string `json:"annotations"`
-	StartsAt     string            `json:"startsAt"`
-	EndsAt       string            `json:"endsAt"`
-	Fingerprint  string            `json:"fingerprint"`
-	GeneratorURL string            `json:"generatorURL,omitempty"`
-}
-
-func notifyWebhook(msg *alertMsg) (err error) {
-	if !msg.wh {
-		return nil
-	}
-	if !shouldNotify(msg, wh) {
-		return nil
-	}
-
-	// Skip sending resolved messages if disabled in config
-	if msg.resolved && boolVal(msg.alertConfig.Webhook.DisableResolveMessage) {
-		return nil
-	}
-
-	status := "firing"
-	if msg.resolved {
-		status = "resolved"
-	}
-
-	now := time.Now().UTC().Format(time.RFC3339)
-	endsAt := "0001-01-01T00:00:00Z"
-	if msg.resolved {
-		endsAt = now
-	}
-
-	alert := WebhookAlert{
-		Status: status,
-		Labels: map[string]string{
-			"alertname":       msg.uniqueId,
-			"chain":           msg.chain,
-			"chain_name":      msg.chainName,
-			"valoper_address": msg.valoperAddress,
-			"valcons_address": msg.valconsAddress,
-			"severity":        msg.severity,
-			"source":          "tenderduty",
-		},
-		Annotations: map[string]string{
-			"summary":     msg.message,
-			"description": msg.message,
-		},
-		StartsAt:    now,
-		EndsAt:      endsAt,
-		Fingerprint: msg.uniqueId,
-	}
-
-	payload := WebhookPayload{
-		Status:   status,
-		Alerts:   []WebhookAlert{alert},
-		Version:  "1",
-		GroupKey: msg.chain,
-	}
-
-	data, err := json.Marshal(payload)
-	if err != nil {
-		l(slog.LevelWarn, "⚠️ Could not marshal webhook payload!", err)
-		return err
-	}
-
-	req, err := http.NewRequest("POST", msg.whURL, bytes.NewBuffer(data))
-	if err != nil {
-		l(slog.LevelWarn, "⚠️ Could not create webhook request!", err)
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		l(slog.LevelWarn, "⚠️ Could not send webhook!", err)
-		return err
+	cache := alertMsgCache{
+		Message:  msg.message,
+		Severity: msg.severity,
+		SentTime: time.Now(),
 	}
-	_ = resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		l(slog.LevelWarn, "⚠️ Webhook returned non-success status:", resp.StatusCode)
-		return fmt.Errorf("webhook returned status %d for %s", resp.StatusCode, msg.chain)
+	if prev, ok := whichMap[msg.uniqueId]; ok {
+		cache.Repeats = prev.Repeats + 1
 	}
-
-	return nil
+	whichMap[msg.uniqueId] = cache
 }
 
 func getAlarms(chain string) string {
 	alarms.notifyMux.RLock()
 	defer alarms.notifyMux.RUnlock()
 	// don't show this info if the logs are disabled on the dashboard, potentially sensitive info could be leaked.
-	if td.HideLogs || alarms.AllAlarms[chain] == nil {
+	if td.HideLogs {
 		return ""
 	}
+	var chainId, valoper string
+	if cc := td.Chains[chain]; cc != nil {
+		chainId, valoper = cc.ChainId, cc.ValAddress
+	}
 	result := ""
 	for k := range alarms.AllAlarms[chain] {
-		result += "🚨 " + alarms.AllAlarms[chain][k].Message + "\n"
+		icon := "🚨 "
+		if silences.match(chain, chainId, k, valoper) != nil {
+			icon = "🔕 "
+		} else if alarms.AllAlarms[chain][k].AckedBy != "" {
+			icon = "👀 "
+		}
+		result += icon + alarms.AllAlarms[chain][k].Message + "\n"
+	}
+	for _, p := range alarms.pending[chain] {
+		state := "firing"
+		if p.resolved {
+			state = "resolving"
+		}
+		result += fmt.Sprintf("⏳ %s (pending %s for %s)\n", p.message, state, time.Since(p.since).Round(time.Second))
 	}
 	return result
 }
 
+// newAlertMsg builds the alert for a chain, the caller must hold chainsMux.
+func (c *Config) newAlertMsg(configName string, cc *ChainConfig, message, severity string, resolved bool, id string) *alertMsg {
+	valcons, moniker := "", ""
+	if cc.valInfo != nil {
+		valcons, moniker = cc.valInfo.Valcons, cc.valInfo.Moniker
+	}
+	return &alertMsg{
+		notifiers:      enabledNotifiers(&c.DefaultAlertConfig, &cc.Alerts),
+		severity:       severity,
+		resolved:       resolved,
+		chain:          fmt.Sprintf("%s (%s)", configName, cc.ChainId),
+		configName:     configName,
+		chainId:        cc.ChainId,
+		chainName:      cc.ChainName,
+		valoperAddress: cc.ValAddress,
+		valconsAddress: valcons,
+		moniker:        moniker,
+		chainLabels:    cc.Labels,
+		message:        message,
+		uniqueId:       id,
+		alertDetails:   alertDetails{kind: kindOf(id)},
+		alertConfig:    &cc.Alerts,
+	}
+}
+
 // alert creates a universal alert and pushes it to the alertChan to be delivered to appropriate services
-func (c *Config) alert(configName, message, severity string, resolved bool, id *string) {
+func (c *Config) alert(configName, message, severity string, resolved bool, id *string, details alertDetails) {
 	if id == nil {
 		return
 	}
@@ -537,35 +460,48 @@ func (c *Config) alert(configName, message, severity string, resolved bool, id *
 		c.chainsMux.RUnlock()
 		return
 	}
-	valcons := ""
-	if cc.valInfo != nil {
-		valcons = cc.valInfo.Valcons
-	}
-	a := &alertMsg{
-		pd:             boolVal(c.DefaultAlertConfig.Pagerduty.Enabled) && boolVal(cc.Alerts.Pagerduty.Enabled),
-		disc:           boolVal(c.DefaultAlertConfig.Discord.Enabled) && boolVal(cc.Alerts.Discord.Enabled),
-		tg:             boolVal(c.DefaultAlertConfig.Telegram.Enabled) && boolVal(cc.Alerts.Telegram.Enabled),
-		slk:            boolVal(c.DefaultAlertConfig.Slack.Enabled) && boolVal(cc.Alerts.Slack.Enabled),
-		wh:             boolVal(c.DefaultAlertConfig.Webhook.Enabled) && boolVal(cc.Alerts.Webhook.Enabled),
-		severity:       severity,
-		resolved:       resolved,
-		chain:          fmt.Sprintf("%s (%s)", configName, cc.ChainId),
-		chainName:      cc.ChainName,
-		valoperAddress: cc.ValAddress,
-		valconsAddress: valcons,
-		message:        message,
-		uniqueId:       *id,
-		key:            cc.Alerts.Pagerduty.ApiKey,
-		tgChannel:      cc.Alerts.Telegram.Channel,
-		tgKey:          cc.Alerts.Telegram.ApiKey,
-		tgMentions:     strings.Join(cc.Alerts.Telegram.Mentions, " "),
-		discHook:       cc.Alerts.Discord.Webhook,
-		discMentions:   strings.Join(cc.Alerts.Discord.Mentions, " "),
-		slkHook:        cc.Alerts.Slack.Webhook,
-		whURL:          cc.Alerts.Webhook.URL,
-		alertConfig:    &cc.Alerts,
+	if details.kind == "" {
+		details.kind = kindOf(*id)
+	}
+	if s := cc.Alerts.Severities[string(details.kind)]; s != "" {
+		severity = s
+	}
+	a := c.newAlertMsg(configName, cc, message, severity, resolved, *id)
+	a.alertDetails = details
+	alarms.notifyMux.Lock()
+	prev, active := alarms.AllAlarms[configName][*id]
+	if alarms.held(a, active, waitFor(&cc.Alerts, details.kind, resolved)) {
+		alarms.notifyMux.Unlock()
+		c.chainsMux.RUnlock()
+		return
+	}
+	if resolved {
+		if alarms.inhibited[configName][*id] != nil {
+			a.inhibited = true
+			delete(alarms.inhibited[configName], *id)
+		}
+		// escalation stages that were notified need the resolution too
+		a.notifiers = append(a.notifiers, alarms.escalatedNotifiers(&cc.Alerts.Escalation, &cc.Alerts, *id)...)
+	}
+	alarms.notifyMux.Unlock()
+	switch {
+	case resolved && active && !prev.SentTime.IsZero():
+		a.eventID = history.record(a, prev.SentTime)
+	case !resolved && !active:
+		a.eventID = history.record(a, time.Time{})
+	case !resolved:
+		a.eventID = prev.EventID
+	}
+	// fired or resolved, as opposed to raised again
+	send := a
+	if resolved == active {
+		alarms.notifyMux.Lock()
+		send = alarms.flapped(a)
+		alarms.notifyMux.Unlock()
+	}
+	if send != nil {
+		c.alertChan <- send
 	}
-	c.alertChan <- a
 	c.chainsMux.RUnlock()
 	alarms.notifyMux.Lock()
 	defer alarms.notifyMux.Unlock()
@@ -573,14 +509,36 @@ func (c *Config) alert(configName, message, severity string, resolved bool, id *
 		alarms.AllAlarms[configName] = make(map[string]alertMsgCache)
 	}
 	if resolved && !alarms.AllAlarms[configName][*id].SentTime.IsZero() {
+		alarms.total(configName).resolvedSeconds += time.Since(alarms.AllAlarms[configName][*id].SentTime).Seconds()
 		delete(alarms.AllAlarms[configName], *id)
+		// alerts that were held back by this one are delivered if they are still active
+		if released := alarms.releaseInhibited(configName); len(released) > 0 {
+			go func() {
+				for _, msg := range released {
+					c.alertChan <- msg
+				}
+			}()
+		}
 		return
 	} else if resolved {
 		return
 	}
 	cache := alertMsgCache{
-		Message:  message,
-		SentTime: time.Now(),
+		Message:   message,
+		Severity:  severity,
+		SentTime:  time.Now(),
+		Kind:      details.kind,
+		Labels:    details.labels,
+		Value:     details.value,
+		Threshold: details.threshold,
+		EventID:   a.eventID,
+	}
+	// keep when the alert was first seen, escalations are timed from it
+	if prev, ok := alarms.AllAlarms[configName][*id]; ok {
+		cache.SentTime = prev.SentTime
+		cache.AckedBy = prev.AckedBy
+	} else {
+		alarms.total(configName).fired += 1
 	}
 	alarms.AllAlarms[configName][*id] = cache
 }
@@ -589,8 +547,9 @@ func evaluateConsecutiveBlocksMissedAlert(cc *ChainConfig) (bool, bool) {
 	alert, resolved := false, false
 
 	alertID := fmt.Sprintf("ConsecutiveBlocksMissed_%s", cc.ValAddress)
+	details := alertDetails{kind: kindConsecutiveBlocksMissed, value: cc.statConsecutiveMiss, threshold: float64(intVal(cc.Alerts.ConsecutiveMissed))}
 	if int(cc.statConsecutiveMiss) >= intVal(cc.Alerts.ConsecutiveMissed) {
-		if !alarms.exist(cc.name, alertID) {
+		if !alarms.exist(cc.name, alertID) || alarms.isPending(cc.name, alertID) {
 			// alert on missed block counter!
 			td.alert(
 				cc.name,
@@ -598,11 +557,12 @@ func evaluateConsecutiveBlocksMissedAlert(cc *ChainConfig) (bool, bool) {
 				cc.Alerts.ConsecutivePriority,
 				false,
 				&alertID,
+				details,
 			)
 			alert = true
 		}
 	} else {
-		if alarms.exist(cc.name, alertID) {
+		if alarms.exist(cc.name, alertID) || alarms.isPending(cc.name, alertID) {
 			// clear the alert
 			td.alert(
 				cc.name,
@@ -610,6 +570,7 @@ func evaluateConsecutiveBlocksMissedAlert(cc *ChainConfig) (bool, bool) {
 				cc.Alerts.ConsecutivePriority,
 				true,
 				&alertID,
+				details,
 			)
 			resolved = true
 		}
@@ -623,8 +584,12 @@ func evaluatePercentageBlocksMissedAlert(cc *ChainConfig) (bool, bool) {
 	alert, resolved := false, false
 
 	alertID := fmt.Sprintf("PercentageBlocksMissed_%s", cc.ValAddress)
+	details := alertDetails{kind: kindPercentageBlocksMissed, threshold: float64(intVal(cc.Alerts.Window))}
+	if cc.valInfo.Window > 0 {
+		details.value = 100 * float64(cc.valInfo.Missed) / float64(cc.valInfo.Window)
+	}
 	if 100*float64(cc.valInfo.Missed)/float64(cc.valInfo.Window) >= float64(intVal(cc.Alerts.Window)) {
-		if !alarms.exist(cc.name, alertID) {
+		if !alarms.exist(cc.name, alertID) || alarms.isPending(cc.name, alertID) {
 			// alert on missed block counter!
 			td.alert(
 				cc.name,
@@ -632,17 +597,19 @@ func evaluatePercentageBlocksMissedAlert(cc *ChainConfig) (bool, bool) {
 				cc.Alerts.PercentagePriority,
 				false,
 				&alertID,
+				details,
 			)
 			alert = true
 		}
 	} else {
-		if alarms.exist(cc.name, alertID) {
+		if alarms.exist(cc.name, alertID) || alarms.isPending(cc.name, alertID) {
 			td.alert(
 				cc.name,
 				fmt.Sprintf("%s has missed > %d%% of the slashing window's blocks on %s", cc.valInfo.Moniker, intVal(cc.Alerts.Window), cc.ChainId),
 				cc.Alerts.PercentagePriority,
 				true,
 				&alertID,
+				details,
 			)
 			resolved = true
 		}
@@ -670,6 +637,7 @@ func evaluateNoRPCEndpointsAlert(cc *ChainConfig, noNodesSec *int) (bool, bool)
 					"critical",
 					false,
 					&alertID,
+					alertDetails{kind: kindNoRPCEndpoints, value: float64(*noNodesSec) / 60, threshold: float64(td.NodeDownMin)},
 				)
 				alert = true
 			}
@@ -682,6 +650,7 @@ func evaluateNoRPCEndpointsAlert(cc *ChainConfig, noNodesSec *int) (bool, bool)
 				"critical",
 				true,
 				&alertID,
+				alertDetails{kind: kindNoRPCEndpoints, value: float64(*noNodesSec) / 60, threshold: float64(td.NodeDownMin)},
 			)
 			resolved = true
 		}
@@ -697,6 +666,7 @@ func evaluateChainStalledAlert(cc *ChainConfig) (bool, bool) {
 
 	if !cc.lastBlockTime.IsZero() {
 		alertID := fmt.Sprintf("ChainStalled_%s", cc.ValAddress)
+		details := alertDetails{kind: kindChainStalled, value: time.Since(cc.lastBlockTime).Minutes(), threshold: float64(intVal(cc.Alerts.Stalled))}
 		if !cc.lastBlockAlarm && cc.lastBlockTime.Before(time.Now().Add(time.Duration(-intVal(cc.Alerts.Stalled))*time.Minute)) {
 			cc.lastBlockAlarm = true
 			td.alert(
@@ -705,6 +675,7 @@ func evaluateChainStalledAlert(cc *ChainConfig) (bool, bool) {
 				"critical",
 				false,
 				&alertID,
+				details,
 			)
 			alert = true
 		} else if !cc.lastBlockTime.Before(time.Now().Add(time.Duration(-intVal(cc.Alerts.Stalled)) * time.Minute)) {
@@ -723,11 +694,12 @@ func evaluateValidatorInactiveAlert(cc *ChainConfig) (bool, bool) {
 
 	if cc.lastValInfo != nil && cc.lastValInfo.Bonded != cc.valInfo.Bonded &&
 		cc.lastValInfo.Moniker == cc.valInfo.Moniker {
-		inactive := "jailed"
+		inactive, status := "jailed", "jailed"
 		alertID := fmt.Sprintf("ValidatorInactive_%s", cc.ValAddress)
 		if !cc.valInfo.Bonded && cc.lastValInfo.Bonded {
 			if cc.valInfo.Tombstoned {
 				inactive = "☠️ tombstoned 🪦"
+				status = "tombstoned"
 			}
 			td.alert(
 				cc.name,
@@ -735,6 +707,7 @@ func evaluateValidatorInactiveAlert(cc *ChainConfig) (bool, bool) {
 				"critical",
 				false,
 				&alertID,
+				alertDetails{kind: kindValidatorInactive, labels: map[string]string{"status": status}},
 			)
 			alert = true
 		} else if cc.valInfo.Bonded && !cc.lastValInfo.Bonded {
@@ -744,6 +717,7 @@ func evaluateValidatorInactiveAlert(cc *ChainConfig) (bool, bool) {
 				"critical",
 				true,
 				&alertID,
+				alertDetails{kind: kindValidatorInactive, labels: map[string]string{"status": status}},
 			)
 			resolved = true
 		}
@@ -757,25 +731,64 @@ func evaluateConsecutiveEmptyBlocksAlert(cc *ChainConfig) (bool, bool) {
 	alert, resolved := false, false
 
 	alertID := fmt.Sprintf("ConsecutiveEmptyBlocks_%s", cc.ValAddress)
+	details := alertDetails{kind: kindConsecutiveEmptyBlocks, value: cc.statConsecutiveEmpty, threshold: float64(intVal(cc.Alerts.ConsecutiveEmpty))}
 	if int(cc.statConsecutiveEmpty) >= intVal(cc.Alerts.ConsecutiveEmpty) {
-		if !alarms.exist(cc.name, alertID) {
+		if !alarms.exist(cc.name, alertID) || alarms.isPending(cc.name, alertID) {
 			td.alert(
 				cc.name,
 				fmt.Sprintf("%s has proposed %d consecutive empty blocks on %s", cc.valInfo.Moniker, intVal(cc.Alerts.ConsecutiveEmpty), cc.ChainId),
 				cc.Alerts.ConsecutiveEmptyPriority,
 				false,
 				&alertID,
+				details,
 			)
 			alert = true
 		}
 	} else {
-		if alarms.exist(cc.name, alertID) {
+		if alarms.exist(cc.name, alertID) || alarms.isPending(cc.name, alertID) {
 			td.alert(
 				cc.name,
 				fmt.Sprintf("%s has proposed %d consecutive empty blocks on %s", cc.valInfo.Moniker, intVal(cc.Alerts.ConsecutiveEmpty), cc.ChainId),
 				cc.Alerts.ConsecutiveEmptyPriority,
 				true,
 				&alertID,
+				details,
+			)
+			resolved = true
+		}
+	}
+
+	cc.activeAlerts = alarms.getCount(cc.name)
+	return alert, resolved
+}
+
+func evaluateMissedProposalsAlert(cc *ChainConfig) (bool, bool) {
+	alert, resolved := false, false
+
+	threshold := max(intVal(cc.Alerts.MissedProposals), 1)
+	alertID := fmt.Sprintf("MissedProposals_%s", cc.ValAddress)
+	details := alertDetails{kind: kindMissedProposals, value: cc.statConsecutiveMissedProps, threshold: float64(threshold)}
+	if int(cc.statConsecutiveMissedProps) >= threshold {
+		if !alarms.exist(cc.name, alertID) {
+			td.alert(
+				cc.name,
+				fmt.Sprintf("%s has missed %d consecutive proposals on %s", cc.valInfo.Moniker, threshold, cc.ChainId),
+				cc.Alerts.MissedProposalsPriority,
+				false,
+				&alertID,
+				details,
+			)
+			alert = true
+		}
+	} else {
+		if alarms.exist(cc.name, alertID) {
+			td.alert(
+				cc.name,
+				fmt.Sprintf("%s has missed %d consecutive proposals on %s", cc.valInfo.Moniker, threshold, cc.ChainId),
+				cc.Alerts.MissedProposalsPriority,
+				true,
+				&alertID,
+				details,
 			)
 			resolved = true
 		}
@@ -794,8 +807,9 @@ func evaluatePercentageEmptyBlocksAlert(cc *ChainConfig) (bool, bool) {
 	}
 
 	alertID := fmt.Sprintf("PercentageEmptyBlocks_%s", cc.ValAddress)
+	details := alertDetails{kind: kindPercentageEmptyBlocks, value: emptyBlocksPercent, threshold: float64(intVal(cc.Alerts.EmptyWindow))}
 	if emptyBlocksPercent >= float64(intVal(cc.Alerts.EmptyWindow)) {
-		if !alarms.exist(cc.name, alertID) {
+		if !alarms.exist(cc.name, alertID) || alarms.isPending(cc.name, alertID) {
 			td.alert(
 				cc.name,
 				fmt.Sprintf("%s has > %d%% empty blocks (%d of %d proposed blocks) on %s",
@@ -807,11 +821,12 @@ func evaluatePercentageEmptyBlocksAlert(cc *ChainConfig) (bool, bool) {
 				cc.Alerts.EmptyPercentagePriority,
 				false,
 				&alertID,
+				details,
 			)
 			alert = true
 		}
 	} else {
-		if alarms.exist(cc.name, alertID) {
+		if alarms.exist(cc.name, alertID) || alarms.isPending(cc.name, alertID) {
 			td.alert(
 				cc.name,
 				fmt.Sprintf("%s has > %d%% empty blocks (%d of %d proposed blocks) on %s",
@@ -823,6 +838,7 @@ func evaluatePercentageEmptyBlocksAlert(cc *ChainConfig) (bool, bool) {
 				cc.Alerts.EmptyPercentagePriority,
 				true,
 				&alertID,
+				details,
 			)
 			resolved = true
 		}
@@ -837,6 +853,10 @@ func evaluateRPCNodeDownAlert(cc *ChainConfig) (bool, bool) {
 
 	for _, node := range cc.Nodes {
 		alertID := fmt.Sprintf("RPCNodeDown_%s_%s", cc.ValAddress, node.Url)
+		details := alertDetails{kind: kindRPCNodeDown, labels: map[string]string{"node": node.Url}, threshold: float64(td.NodeDownMin)}
+		if !node.downSince.IsZero() {
+			details.value = time.Since(node.downSince).Minutes()
+		}
 		if node.AlertIfDown && node.down && !node.wasDown && !node.downSince.IsZero() &&
 			time.Since(node.downSince) > time.Duration(td.NodeDownMin)*time.Minute {
 			if !alarms.exist(cc.name, alertID) {
@@ -846,6 +866,7 @@ func evaluateRPCNodeDownAlert(cc *ChainConfig) (bool, bool) {
 					td.NodeDownSeverity,
 					false,
 					&alertID,
+					details,
 				)
 				alert = true
 			}
@@ -858,6 +879,7 @@ func evaluateRPCNodeDownAlert(cc *ChainConfig) (bool, bool) {
 					td.NodeDownSeverity,
 					true,
 					&alertID,
+					details,
 				)
 				resolved = true
 			}
@@ -882,6 +904,12 @@ func evaluateStakeChangeAlert(cc *ChainConfig) (bool, bool) {
 			threshold = floatVal(cc.Alerts.StakeChangeDropThreshold)
 		}
 		alertID := fmt.Sprintf("StakeChange_%s", cc.ValAddress)
+		details := alertDetails{
+			kind:      kindStakeChange,
+			labels:    map[string]string{"trend": trend},
+			value:     math.Abs(stakeChangePercent) * 100,
+			threshold: threshold * 100,
+		}
 		severity := "warning"
 		unit := "base"
 		if cc.denomMetadata != nil && cc.Provider.Name != "namada" {
@@ -901,12 +929,12 @@ func evaluateStakeChangeAlert(cc *ChainConfig) (bool, bool) {
 		message := fmt.Sprintf("%s's stake has %s by %.1f%% (%s %s now) compared to the previous check (%s %s)", cc.valInfo.Moniker, trend, math.Abs(stakeChangePercent)*100, utils.HumanSI(stakeNow), unit, utils.HumanSI(stakeBefore), unit)
 		if math.Abs(stakeChangePercent) >= threshold {
 			if !alarms.exist(cc.name, alertID) {
-				td.alert(cc.name, message, severity, false, &alertID)
+				td.alert(cc.name, message, severity, false, &alertID, details)
 				alert = true
 			}
 		} else {
 			if alarms.exist(cc.name, alertID) {
-				td.alert(cc.name, message, severity, true, &alertID)
+				td.alert(cc.name, message, severity, true, &alertID, details)
 				resolved = true
 			}
 		}
@@ -919,6 +947,42 @@ func evaluateStakeChangeAlert(cc *ChainConfig) (bool, bool) {
 func evaluateUnclaimedRewardsAlert(cc *ChainConfig) (bool, bool) {
 	alert, resolved := false, false
 
+	totalRewardsConverted, ok := cc.unclaimedRewardsInFiat()
+	if !ok {
+		return alert, resolved
+	}
+	threshold := floatVal(cc.Alerts.UnclaimedRewardsThreshold)
+
+	alertID := fmt.Sprintf("UnclaimedRewards_%s", cc.ValAddress)
+	details := alertDetails{kind: kindUnclaimedRewards, value: totalRewardsConverted, threshold: threshold}
+	const severity = "warning"
+	if totalRewardsConverted > threshold {
+		if !alarms.exist(cc.name, alertID) || alarms.isPending(cc.name, alertID) {
+			message := fmt.Sprintf("%s has more than %.0f (%.0f currently) %s unclaimed rewards on %s",
+				cc.valInfo.Moniker, threshold, totalRewardsConverted, td.PriceConversion.Currency, cc.name)
+			td.alert(cc.name, message, severity, false, &alertID, details)
+			alert = true
+		}
+	} else {
+		if alarms.exist(cc.name, alertID) || alarms.isPending(cc.name, alertID) {
+			message := fmt.Sprintf("%s has more than %.0f %s unclaimed rewards on %s",
+				cc.valInfo.Moniker, threshold, td.PriceConversion.Currency, cc.name)
+			td.alert(cc.name, message, severity, true, &alertID, details)
+			resolved = true
+		}
+	}
+
+	cc.activeAlerts = alarms.getCount(cc.name)
+
+	return alert, resolved
+}
+
+// unclaimedRewardsInFiat returns the validator's self-delegation rewards and commission in the fiat currency, ok is false
+// when they cannot be priced.
+func (cc *ChainConfig) unclaimedRewardsInFiat() (value float64, ok bool) {
+	if td.coinMarketCapClient == nil || cc.valInfo == nil {
+		return 0, false
+	}
 	selfRewardsLen := 0
 	commissionLen := 0
 	if cc.valInfo.SelfDelegationRewards != nil {
@@ -983,7 +1047,7 @@ func evaluateUnclaimedRewardsAlert(cc *ChainConfig) (bool, bool) {
 		}
 
 		if len(targetDenoms) == 0 || targetDenom == "" {
-			return alert, resolved
+			return 0, false
 		}
 
 		var nativeCoins []github_com_cosmos_cosmos_sdk_types.DecCoin
@@ -1003,7 +1067,7 @@ func evaluateUnclaimedRewardsAlert(cc *ChainConfig) (bool, bool) {
 		}
 
 		if len(nativeCoins) == 0 {
-			return alert, resolved
+			return 0, false
 		}
 
 		convertedToDisplay := false
@@ -1023,45 +1087,24 @@ func evaluateUnclaimedRewardsAlert(cc *ChainConfig) (bool, bool) {
 		}
 
 		if totalAmount.IsZero() {
-			return alert, resolved
+			return 0, false
 		}
 
 		// coinPrice is for the chain display token. If metadata exists but conversion to display
 		// failed, the amount may still be in base units (e.g. uom), which would misprice by 10^exp.
-		// Skip this alert evaluation rather than raising a false amount-based alert.
+		// Skip pricing rather than reporting a wrong amount.
 		if cc.denomMetadata != nil && !convertedToDisplay {
 			l(slog.LevelDebug, fmt.Errorf("skipping unclaimed rewards pricing for %s because rewards are not confirmed in display units", cc.name))
-			return alert, resolved
+			return 0, false
 		}
 
 		coinPrice, err := td.coinMarketCapClient.GetPrice(td.ctx, cc.Slug)
-		if err == nil && convertedToDisplay {
-			totalRewardsConverted := totalAmount.MustFloat64() * coinPrice.Price
-			threshold := floatVal(cc.Alerts.UnclaimedRewardsThreshold)
-
-			alertID := fmt.Sprintf("UnclaimedRewards_%s", cc.ValAddress)
-			const severity = "warning"
-			if totalRewardsConverted > threshold {
-				if !alarms.exist(cc.name, alertID) {
-					message := fmt.Sprintf("%s has more than %.0f (%.0f currently) %s unclaimed rewards on %s",
-						cc.valInfo.Moniker, threshold, totalRewardsConverted, td.PriceConversion.Currency, cc.name)
-					td.alert(cc.name, message, severity, false, &alertID)
-					alert = true
-				}
-			} else {
-				if alarms.exist(cc.name, alertID) {
-					message := fmt.Sprintf("%s has more than %.0f %s unclaimed rewards on %s",
-						cc.valInfo.Moniker, threshold, td.PriceConversion.Currency, cc.name)
-					td.alert(cc.name, message, severity, true, &alertID)
-					resolved = true
-				}
-			}
-
-			cc.activeAlerts = alarms.getCount(cc.name)
+		if err != nil || !convertedToDisplay {
+			return 0, false
 		}
+		return totalAmount.MustFloat64() * coinPrice.Price, true
 	}
-
-	return alert, resolved
+	return 0, false
 }
 
 func evaluateUnvotedGovernanceProposalAlert(cc *ChainConfig) (bool, bool) {
@@ -1070,13 +1113,10 @@ func evaluateUnvotedGovernanceProposalAlert(cc *ChainConfig) (bool, bool) {
 	idTemplate := "UnvotedGovernanceProposal_%s_%d"
 	msgTemplate := "[WARNING] There is an open proposal (#%v) that the validator has not voted on %s%s"
 
-	unvotedProposalMap := make(map[uint64]bool)
-	for _, proposal := range cc.unvotedOpenGovProposals {
-		unvotedProposalMap[proposal.ProposalId] = true
-	}
-
+	unvotedAlertIDs := make(map[string]bool)
 	for _, proposal := range cc.unvotedOpenGovProposals {
 		alertID := fmt.Sprintf(idTemplate, cc.ValAddress, proposal.ProposalId)
+		unvotedAlertIDs[alertID] = true
 		deadline := fmt.Sprintf(", deadline: %s UTC", proposal.VotingEndTime.Format("2006-01-02 15:04"))
 		if cc.Provider.Name == "namada" {
 			deadline = ""
@@ -1090,39 +1130,41 @@ func evaluateUnvotedGovernanceProposalAlert(cc *ChainConfig) (bool, bool) {
 				"warning",
 				false,
 				&alertID,
+				alertDetails{
+					kind:   kindUnvotedGovernanceProposal,
+					labels: map[string]string{"proposal_id": strconv.FormatUint(proposal.ProposalId, 10)},
+				},
 			)
 			alert = true
 		}
 	}
 
-	messagesToBeResolved := make(map[uint64]string)
+	type toResolve struct {
+		id    string
+		cache alertMsgCache
+	}
+	messagesToBeResolved := make([]toResolve, 0)
 
 	alarms.notifyMux.RLock()
 
-	if alarms.AllAlarms[cc.name] != nil {
-		for alertID := range alarms.AllAlarms[cc.name] {
-			if strings.HasPrefix(alertID, "UnvotedGovernanceProposal") {
-				parts := strings.Split(alertID, "_")
-				if proposalID, err := strconv.ParseUint(parts[len(parts)-1], 10, 64); err == nil {
-					if !unvotedProposalMap[proposalID] {
-						messagesToBeResolved[proposalID] = alertID
-					}
-				}
-			}
+	for alertID, cache := range alarms.AllAlarms[cc.name] {
+		if cache.details(alertID).kind == kindUnvotedGovernanceProposal && !unvotedAlertIDs[alertID] {
+			messagesToBeResolved = append(messagesToBeResolved, toResolve{id: alertID, cache: cache})
 		}
 	}
 
 	alarms.notifyMux.RUnlock()
 
-	for _, alertID := range messagesToBeResolved {
-		if alarms.exist(cc.name, alertID) {
-			alertIDCopy := alertID // Create local copy to avoid implicit memory aliasing
+	for _, r := range messagesToBeResolved {
+		if alarms.exist(cc.name, r.id) {
+			alertIDCopy := r.id // Create local copy to avoid implicit memory aliasing
 			td.alert(
 				cc.name,
-				alarms.AllAlarms[cc.name][alertID].Message,
+				r.cache.Message,
 				"warning",
 				true,
 				&alertIDCopy,
+				r.cache.details(r.id),
 			)
 			resolved = true
 		}
@@ -1150,6 +1192,7 @@ func (cc *ChainConfig) watch() {
 						"critical",
 						false,
 						&alertID,
+						alertDetails{kind: kindNoRPCEndpoints, value: float64(noNodesSec) / 60, threshold: float64(td.NodeDownMin)},
 					)
 				}
 			}
@@ -1205,6 +1248,11 @@ func (cc *ChainConfig) watch() {
 			evaluatePercentageEmptyBlocksAlert(cc)
 		}
 
+		// round 0 proposals that another validator's block replaced
+		if boolVal(cc.Alerts.MissedProposalsAlerts) {
+			evaluateMissedProposalsAlert(cc)
+		}
+
 		// node down alarms
 		evaluateRPCNodeDownAlert(cc)
 
@@ -1223,6 +1271,9 @@ func (cc *ChainConfig) watch() {
 			evaluateUnvotedGovernanceProposalAlert(cc)
 		}
 
+		// double sign and evidence alerts expire
+		evaluateEvidenceAlert(cc)
+
 		if td.Prom {
 			// raw block timer, ignoring finalized state
 			td.statsChan <- cc.mkUpdate(metricLastBlockSecondsNotFinal, time.Since(cc.lastBlockTime).Seconds(), "")
@@ -1,11 +1,17 @@
 package tenderduty
 
 import (
+	"bufio"
 	"bytes"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
+	"net"
 	"net/http"
 	"reflect"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -112,6 +118,20 @@ func TestSeverityThresholdToSeverities(t *testing.T) {
 	}
 }
 
+// newTestAlarmCache replaces the alarm cache with an empty one for a test, the original is put back when the test
+// is done.
+func newTestAlarmCache(t *testing.T) *alarmCache {
+	original := alarms
+	alarms = &alarmCache{
+		Sent:      make(map[string]map[string]alertMsgCache),
+		AllAlarms: make(map[string]map[string]alertMsgCache),
+		flaps:     make(map[string]map[string]*flapState),
+		notifyMux: sync.RWMutex{},
+	}
+	t.Cleanup(func() { alarms = original })
+	return alarms
+}
+
 func TestAlarmCacheGetCount(t *testing.T) {
 	cache := &alarmCache{
 		AllAlarms: map[string]map[string]alertMsgCache{
@@ -181,26 +201,12 @@ func TestAlarmCacheClearAll(t *testing.T) {
 }
 
 func TestShouldNotify(t *testing.T) {
-	// Setup test alarm cache
-	testAlarms := &alarmCache{
-		SentPdAlarms:   make(map[string]alertMsgCache),
-		SentTgAlarms:   make(map[string]alertMsgCache),
-		SentDiAlarms:   make(map[string]alertMsgCache),
-		SentSlkAlarms:  make(map[string]alertMsgCache),
-		SentWHAlarms:   make(map[string]alertMsgCache),
-		AllAlarms:      make(map[string]map[string]alertMsgCache),
-		flappingAlarms: make(map[string]map[string]alertMsgCache),
-		notifyMux:      sync.RWMutex{},
-	}
-	// Replace global alarms for testing
-	originalAlarms := alarms
-	alarms = testAlarms
-	defer func() { alarms = originalAlarms }()
+	testAlarms := newTestAlarmCache(t)
 
 	tests := []struct {
 		name        string
 		msg         *alertMsg
-		dest        notifyDest
+		dest        Notifier
 		setupAlarms func()
 		expected    bool
 		description string
@@ -215,7 +221,7 @@ func TestShouldNotify(t *testing.T) {
 					Pagerduty: PDConfig{SeverityThreshold: "critical"},
 				},
 			},
-			dest:        pd,
+			dest:        pagerdutyNotifier{},
 			setupAlarms: func() {},
 			expected:    true,
 			description: "First time sending alert should return true",
@@ -230,9 +236,9 @@ func TestShouldNotify(t *testing.T) {
 					Pagerduty: PDConfig{SeverityThreshold: "critical"},
 				},
 			},
-			dest: pd,
+			dest: pagerdutyNotifier{},
 			setupAlarms: func() {
-				testAlarms.SentPdAlarms["test_alert_2"] = alertMsgCache{
+				testAlarms.sentFor("pagerduty")["test_alert_2"] = alertMsgCache{
 					Message:  "Previous alert",
 					SentTime: time.Now().Add(-1 * time.Hour),
 				}
@@ -250,9 +256,9 @@ func TestShouldNotify(t *testing.T) {
 					Pagerduty: PDConfig{SeverityThreshold: "critical"},
 				},
 			},
-			dest: pd,
+			dest: pagerdutyNotifier{},
 			setupAlarms: func() {
-				testAlarms.SentPdAlarms["test_alert_3"] = alertMsgCache{
+				testAlarms.sentFor("pagerduty")["test_alert_3"] = alertMsgCache{
 					Message:  "Previous alert",
 					SentTime: time.Now().Add(-1 * time.Hour),
 				}
@@ -270,7 +276,7 @@ func TestShouldNotify(t *testing.T) {
 					Pagerduty: PDConfig{SeverityThreshold: "critical"},
 				},
 			},
-			dest:        pd,
+			dest:        pagerdutyNotifier{},
 			setupAlarms: func() {},
 			expected:    false,
 			description: "Alert with severity below threshold should not notify",
@@ -280,12 +286,7 @@ func TestShouldNotify(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Reset alarms for each test
-			testAlarms.SentPdAlarms = make(map[string]alertMsgCache)
-			testAlarms.SentTgAlarms = make(map[string]alertMsgCache)
-			testAlarms.SentDiAlarms = make(map[string]alertMsgCache)
-			testAlarms.SentSlkAlarms = make(map[string]alertMsgCache)
-			testAlarms.SentWHAlarms = make(map[string]alertMsgCache)
-			testAlarms.flappingAlarms = make(map[string]map[string]alertMsgCache)
+			testAlarms.Sent = make(map[string]map[string]alertMsgCache)
 
 			tt.setupAlarms()
 
@@ -297,6 +298,60 @@ func TestShouldNotify(t *testing.T) {
 	}
 }
 
+// notifyAndRecord is shouldNotify followed by a successful delivery.
+func notifyAndRecord(msg *alertMsg, dest Notifier) bool {
+	if !shouldNotify(msg, dest) {
+		return false
+	}
+	alarms.recordSent(msg, dest)
+	return true
+}
+
+func TestEnabledNotifiers(t *testing.T) {
+	enabled, disabled := true, false
+	defaults := &AlertConfig{
+		Pagerduty: PDConfig{Enabled: &enabled},
+		Discord:   DiscordConfig{Enabled: &enabled},
+		Telegram:  TeleConfig{Enabled: &disabled},
+		Slack:     SlackConfig{Enabled: &enabled},
+	}
+	chain := &AlertConfig{
+		Pagerduty: PDConfig{Enabled: &enabled},
+		Discord:   DiscordConfig{Enabled: &disabled},
+		Telegram:  TeleConfig{Enabled: &enabled},
+		Slack:     SlackConfig{Enabled: &enabled},
+		Webhook:   WebhookConfig{Enabled: &enabled},
+	}
+
+	names := make([]string, 0)
+	for _, n := range enabledNotifiers(defaults, chain) {
+		names = append(names, n.Name())
+	}
+	expected := []string{"pagerduty", "slack"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("enabledNotifiers() = %v, want %v", names, expected)
+	}
+}
+
+func TestLegacyAlarmCacheSent(t *testing.T) {
+	legacy := &legacyAlarmCache{
+		SentPdAlarms: map[string]alertMsgCache{"pd_alert": {Message: "pd"}},
+		SentWHAlarms: map[string]alertMsgCache{"wh_alert": {Message: "wh"}},
+	}
+	sent := legacy.sent()
+	for name := range sent {
+		if _, ok := getNotifier(name); !ok {
+			t.Errorf("legacy state maps to unregistered notifier %s", name)
+		}
+	}
+	if sent["pagerduty"]["pd_alert"].Message != "pd" {
+		t.Errorf("expected pagerduty state to be migrated, got %v", sent["pagerduty"])
+	}
+	if sent["webhook"]["wh_alert"].Message != "wh" {
+		t.Errorf("expected webhook state to be migrated, got %v", sent["webhook"])
+	}
+}
+
 func TestBuildSlackMessage(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -306,10 +361,12 @@ func TestBuildSlackMessage(t *testing.T) {
 		{
 			name: "alert message",
 			msg: &alertMsg{
-				chain:       "test-chain",
-				message:     "Test alert message",
-				resolved:    false,
-				slkMentions: "@here",
+				chain:    "test-chain",
+				message:  "Test alert message",
+				resolved: false,
+				alertConfig: &AlertConfig{
+					Slack: SlackConfig{Mentions: []string{"@here"}},
+				},
 			},
 			expected: &SlackMessage{
 				Text: "Test alert message",
@@ -324,10 +381,12 @@ func TestBuildSlackMessage(t *testing.T) {
 		{
 			name: "resolved message",
 			msg: &alertMsg{
-				chain:       "test-chain",
-				message:     "Test resolved message",
-				resolved:    true,
-				slkMentions: "@here",
+				chain:    "test-chain",
+				message:  "Test resolved message",
+				resolved: true,
+				alertConfig: &AlertConfig{
+					Slack: SlackConfig{Mentions: []string{"@here"}},
+				},
 			},
 			expected: &SlackMessage{
 				Text: "OK: Test resolved message",
@@ -403,6 +462,191 @@ func TestBuildDiscordMessage(t *testing.T) {
 	}
 }
 
+func TestBuildMatrixMessage(t *testing.T) {
+	cfg := &AlertConfig{Matrix: MatrixConfig{Mentions: []string{"@oncall:example.org"}}}
+	tests := []struct {
+		name     string
+		msg      *alertMsg
+		expected *MatrixMessage
+	}{
+		{
+			name: "critical alert",
+			msg: &alertMsg{
+				chain:       "test-chain",
+				message:     "Test <alert>",
+				severity:    "critical",
+				alertConfig: cfg,
+			},
+			expected: &MatrixMessage{
+				MsgType:       "m.text",
+				Body:          "🚨 ALERT [CRITICAL]: test-chain: Test <alert> @oncall:example.org",
+				Format:        "org.matrix.custom.html",
+				FormattedBody: "<b>🚨 ALERT [CRITICAL]: test-chain</b><br/>Test &lt;alert&gt; @oncall:example.org",
+			},
+		},
+		{
+			name: "info alert",
+			msg: &alertMsg{
+				chain:       "test-chain",
+				message:     "Test alert",
+				severity:    "info",
+				alertConfig: cfg,
+			},
+			expected: &MatrixMessage{
+				MsgType:       "m.notice",
+				Body:          "🚨 ALERT [INFO]: test-chain: Test alert @oncall:example.org",
+				Format:        "org.matrix.custom.html",
+				FormattedBody: "<b>🚨 ALERT [INFO]: test-chain</b><br/>Test alert @oncall:example.org",
+			},
+		},
+		{
+			name: "resolved message",
+			msg: &alertMsg{
+				chain:       "test-chain",
+				message:     "Test alert",
+				severity:    "critical",
+				resolved:    true,
+				alertConfig: cfg,
+			},
+			expected: &MatrixMessage{
+				MsgType:       "m.notice",
+				Body:          "💜 Resolved [CRITICAL]: test-chain: Test alert",
+				Format:        "org.matrix.custom.html",
+				FormattedBody: "<b>💜 Resolved [CRITICAL]: test-chain</b><br/>Test alert",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := buildMatrixMessage(tt.msg)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("buildMatrixMessage() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildNtfyMessage(t *testing.T) {
+	cfg := &AlertConfig{Ntfy: NtfyConfig{Topic: "validators"}}
+	tests := []struct {
+		name     string
+		msg      *alertMsg
+		expected *NtfyMessage
+	}{
+		{
+			name:     "critical alert",
+			msg:      &alertMsg{chain: "test-chain", message: "Test alert", severity: "critical", alertConfig: cfg},
+			expected: &NtfyMessage{Topic: "validators", Title: "🚨 ALERT: test-chain", Message: "Test alert", Priority: 5, Tags: []string{"rotating_light", "critical"}},
+		},
+		{
+			name:     "warning alert",
+			msg:      &alertMsg{chain: "test-chain", message: "Test alert", severity: "warning", alertConfig: cfg},
+			expected: &NtfyMessage{Topic: "validators", Title: "🚨 ALERT: test-chain", Message: "Test alert", Priority: 4, Tags: []string{"warning", "warning"}},
+		},
+		{
+			name:     "info alert",
+			msg:      &alertMsg{chain: "test-chain", message: "Test alert", severity: "info", alertConfig: cfg},
+			expected: &NtfyMessage{Topic: "validators", Title: "🚨 ALERT: test-chain", Message: "Test alert", Priority: 3, Tags: []string{"information_source", "info"}},
+		},
+		{
+			name:     "resolved message",
+			msg:      &alertMsg{chain: "test-chain", message: "Test alert", severity: "critical", resolved: true, alertConfig: cfg},
+			expected: &NtfyMessage{Topic: "validators", Title: "💜 Resolved: test-chain", Message: "Test alert", Priority: 2, Tags: []string{"white_check_mark", "resolved"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := buildNtfyMessage(tt.msg)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("buildNtfyMessage() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildGotifyMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      *alertMsg
+		expected *GotifyMessage
+	}{
+		{
+			name:     "critical alert",
+			msg:      &alertMsg{chain: "test-chain", message: "Test alert", severity: "critical"},
+			expected: &GotifyMessage{Title: "🚨 ALERT: test-chain", Message: "Test alert", Priority: 8},
+		},
+		{
+			name:     "warning alert",
+			msg:      &alertMsg{chain: "test-chain", message: "Test alert", severity: "warning"},
+			expected: &GotifyMessage{Title: "🚨 ALERT: test-chain", Message: "Test alert", Priority: 5},
+		},
+		{
+			name:     "resolved message",
+			msg:      &alertMsg{chain: "test-chain", message: "Test alert", severity: "critical", resolved: true},
+			expected: &GotifyMessage{Title: "💜 Resolved: test-chain", Message: "OK: Test alert", Priority: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := buildGotifyMessage(tt.msg)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("buildGotifyMessage() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNotifyPushDestinations(t *testing.T) {
+	var requests []*http.Request
+	originalTransport := http.DefaultTransport
+	http.DefaultTransport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewBuffer(nil)),
+			Header:     make(http.Header),
+			Request:    req,
+		}, nil
+	})
+	defer func() { http.DefaultTransport = originalTransport }()
+
+	msg := &alertMsg{
+		chain:    "test-chain",
+		message:  "Test alert",
+		severity: "critical",
+		alertConfig: &AlertConfig{
+			Matrix: MatrixConfig{Homeserver: "https://matrix.test.local/", AccessToken: "mx-token", RoomID: "!room:test.local"},
+			Ntfy:   NtfyConfig{Server: "https://ntfy.test.local", Topic: "validators", Token: "ntfy-token"},
+			Gotify: GotifyConfig{Server: "https://gotify.test.local", Token: "gotify-token"},
+		},
+	}
+
+	for _, n := range []Notifier{matrixNotifier{}, ntfyNotifier{}, gotifyNotifier{}} {
+		if err := n.Send(msg); err != nil {
+			t.Errorf("%s: unexpected error: %v", n.Name(), err)
+		}
+	}
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(requests))
+	}
+
+	if requests[0].Method != "PUT" || !strings.HasPrefix(requests[0].URL.String(), "https://matrix.test.local/_matrix/client/v3/rooms/%21room:test.local/send/m.room.message/") {
+		t.Errorf("unexpected matrix request %s %s", requests[0].Method, requests[0].URL)
+	}
+	if requests[0].Header.Get("Authorization") != "Bearer mx-token" {
+		t.Errorf("unexpected matrix authorization header %s", requests[0].Header.Get("Authorization"))
+	}
+	if requests[1].URL.String() != "https://ntfy.test.local" || requests[1].Header.Get("Authorization") != "Bearer ntfy-token" {
+		t.Errorf("unexpected ntfy request %s", requests[1].URL)
+	}
+	if requests[2].URL.String() != "https://gotify.test.local/message" || requests[2].Header.Get("X-Gotify-Key") != "gotify-token" {
+		t.Errorf("unexpected gotify request %s", requests[2].URL)
+	}
+}
+
 func TestNotifySlack(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -413,12 +657,18 @@ func TestNotifySlack(t *testing.T) {
 		{
 			name: "successful notification",
 			msg: &alertMsg{
-				slk:         true,
-				chain:       "test-chain",
-				message:     "test message",
-				resolved:    false,
-				slkMentions: "@here",
-				slkHook:     "", // will be set to test server URL
+				notifiers: []Notifier{slackNotifier{}},
+				chain:     "test-chain",
+				message:   "test message",
+				severity:  "critical",
+				uniqueId:  "test_slack_1",
+				resolved:  false,
+				alertConfig: &AlertConfig{
+					Slack: SlackConfig{
+						Mentions: []string{"@here"},
+						Webhook:  "", // will be set to test server URL
+					},
+				},
 			},
 			serverResponse: 200,
 			expectError:    false,
@@ -426,12 +676,18 @@ func TestNotifySlack(t *testing.T) {
 		{
 			name: "server error",
 			msg: &alertMsg{
-				slk:         true,
-				chain:       "test-chain",
-				message:     "test message",
-				resolved:    false,
-				slkMentions: "@here",
-				slkHook:     "", // will be set to test server URL
+				notifiers: []Notifier{slackNotifier{}},
+				chain:     "test-chain",
+				message:   "test message",
+				severity:  "critical",
+				uniqueId:  "test_slack_2",
+				resolved:  false,
+				alertConfig: &AlertConfig{
+					Slack: SlackConfig{
+						Mentions: []string{"@here"},
+						Webhook:  "", // will be set to test server URL
+					},
+				},
 			},
 			serverResponse: 500,
 			expectError:    true,
@@ -439,7 +695,7 @@ func TestNotifySlack(t *testing.T) {
 		{
 			name: "slack disabled",
 			msg: &alertMsg{
-				slk: false,
+				notifiers: []Notifier{},
 			},
 			expectError: false,
 		},
@@ -447,7 +703,7 @@ func TestNotifySlack(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if tt.msg.slk {
+			if len(tt.msg.notifiers) > 0 {
 				originalTransport := http.DefaultTransport
 				// Avoid binding a local port; simulate Slack responses via a mock transport.
 				http.DefaultTransport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
@@ -461,10 +717,13 @@ func TestNotifySlack(t *testing.T) {
 				t.Cleanup(func() {
 					http.DefaultTransport = originalTransport
 				})
-				tt.msg.slkHook = "http://slack.test.local/"
+				tt.msg.alertConfig.Slack.Webhook = "http://slack.test.local/"
 			}
 
-			err := notifySlack(tt.msg)
+			var err error
+			for _, n := range tt.msg.notifiers {
+				err = deliver(tt.msg, n)
+			}
 			if tt.expectError && err == nil {
 				t.Errorf("Expected error but got none")
 			}
@@ -487,13 +746,12 @@ func TestNotifyWebhook(t *testing.T) {
 		{
 			name: "successful notification",
 			msg: &alertMsg{
-				wh:       true,
-				chain:    "test-chain",
-				message:  "test message",
-				severity: "critical",
-				uniqueId: "test_alert_1",
-				resolved: false,
-				whURL:    "", // will be set to test server URL
+				notifiers: []Notifier{webhookNotifier{}},
+				chain:     "test-chain",
+				message:   "test message",
+				severity:  "critical",
+				uniqueId:  "test_alert_1",
+				resolved:  false,
 				alertConfig: &AlertConfig{
 					Webhook: WebhookConfig{SeverityThreshold: "info"},
 				},
@@ -505,13 +763,12 @@ func TestNotifyWebhook(t *testing.T) {
 		{
 			name: "server error",
 			msg: &alertMsg{
-				wh:       true,
-				chain:    "test-chain",
-				message:  "test message",
-				severity: "critical",
-				uniqueId: "test_alert_2",
-				resolved: false,
-				whURL:    "", // will be set to test server URL
+				notifiers: []Notifier{webhookNotifier{}},
+				chain:     "test-chain",
+				message:   "test message",
+				severity:  "critical",
+				uniqueId:  "test_alert_2",
+				resolved:  false,
 				alertConfig: &AlertConfig{
 					Webhook: WebhookConfig{SeverityThreshold: "info"},
 				},
@@ -523,7 +780,7 @@ func TestNotifyWebhook(t *testing.T) {
 		{
 			name: "webhook disabled",
 			msg: &alertMsg{
-				wh: false,
+				notifiers: []Notifier{},
 			},
 			expectError:      false,
 			expectServerCall: false,
@@ -531,13 +788,12 @@ func TestNotifyWebhook(t *testing.T) {
 		{
 			name: "resolved message skipped when DisableResolveMessage is true",
 			msg: &alertMsg{
-				wh:       true,
-				chain:    "test-chain",
-				message:  "test resolved message",
-				severity: "critical",
-				uniqueId: "test_alert_resolved_skip",
-				resolved: true,
-				whURL:    "", // will be set to test server URL
+				notifiers: []Notifier{webhookNotifier{}},
+				chain:     "test-chain",
+				message:   "test resolved message",
+				severity:  "critical",
+				uniqueId:  "test_alert_resolved_skip",
+				resolved:  true,
 				alertConfig: &AlertConfig{
 					Webhook: WebhookConfig{
 						SeverityThreshold:     "info",
@@ -550,7 +806,7 @@ func TestNotifyWebhook(t *testing.T) {
 			expectServerCall: false,
 			// Set up existing alert so the resolved message would normally be sent
 			setupAlarms: func(a *alarmCache) {
-				a.SentWHAlarms["test_alert_resolved_skip"] = alertMsgCache{
+				a.sentFor("webhook")["test_alert_resolved_skip"] = alertMsgCache{
 					Message:  "Previous alert",
 					SentTime: time.Now().Add(-1 * time.Hour),
 				}
@@ -559,13 +815,12 @@ func TestNotifyWebhook(t *testing.T) {
 		{
 			name: "resolved message sent when DisableResolveMessage is false",
 			msg: &alertMsg{
-				wh:       true,
-				chain:    "test-chain",
-				message:  "test resolved message",
-				severity: "critical",
-				uniqueId: "test_alert_resolved_send",
-				resolved: true,
-				whURL:    "", // will be set to test server URL
+				notifiers: []Notifier{webhookNotifier{}},
+				chain:     "test-chain",
+				message:   "test resolved message",
+				severity:  "critical",
+				uniqueId:  "test_alert_resolved_send",
+				resolved:  true,
 				alertConfig: &AlertConfig{
 					Webhook: WebhookConfig{
 						SeverityThreshold:     "info",
@@ -578,7 +833,7 @@ func TestNotifyWebhook(t *testing.T) {
 			expectServerCall: true,
 			// Set up existing alert so the resolved message can be sent
 			setupAlarms: func(a *alarmCache) {
-				a.SentWHAlarms["test_alert_resolved_send"] = alertMsgCache{
+				a.sentFor("webhook")["test_alert_resolved_send"] = alertMsgCache{
 					Message:  "Previous alert",
 					SentTime: time.Now().Add(-1 * time.Hour),
 				}
@@ -587,13 +842,12 @@ func TestNotifyWebhook(t *testing.T) {
 		{
 			name: "resolved message sent when DisableResolveMessage is nil (default)",
 			msg: &alertMsg{
-				wh:       true,
-				chain:    "test-chain",
-				message:  "test resolved message",
-				severity: "critical",
-				uniqueId: "test_alert_resolved_nil",
-				resolved: true,
-				whURL:    "", // will be set to test server URL
+				notifiers: []Notifier{webhookNotifier{}},
+				chain:     "test-chain",
+				message:   "test resolved message",
+				severity:  "critical",
+				uniqueId:  "test_alert_resolved_nil",
+				resolved:  true,
 				alertConfig: &AlertConfig{
 					Webhook: WebhookConfig{SeverityThreshold: "info"},
 				},
@@ -603,7 +857,7 @@ func TestNotifyWebhook(t *testing.T) {
 			expectServerCall: true,
 			// Set up existing alert so the resolved message can be sent
 			setupAlarms: func(a *alarmCache) {
-				a.SentWHAlarms["test_alert_resolved_nil"] = alertMsgCache{
+				a.sentFor("webhook")["test_alert_resolved_nil"] = alertMsgCache{
 					Message:  "Previous alert",
 					SentTime: time.Now().Add(-1 * time.Hour),
 				}
@@ -612,13 +866,12 @@ func TestNotifyWebhook(t *testing.T) {
 		{
 			name: "firing message sent even when DisableResolveMessage is true",
 			msg: &alertMsg{
-				wh:       true,
-				chain:    "test-chain",
-				message:  "test firing message",
-				severity: "critical",
-				uniqueId: "test_alert_firing",
-				resolved: false,
-				whURL:    "", // will be set to test server URL
+				notifiers: []Notifier{webhookNotifier{}},
+				chain:     "test-chain",
+				message:   "test firing message",
+				severity:  "critical",
+				uniqueId:  "test_alert_firing",
+				resolved:  false,
 				alertConfig: &AlertConfig{
 					Webhook: WebhookConfig{
 						SeverityThreshold:     "info",
@@ -632,25 +885,12 @@ func TestNotifyWebhook(t *testing.T) {
 		},
 	}
 
-	// Setup test alarm cache
-	testAlarms := &alarmCache{
-		SentPdAlarms:   make(map[string]alertMsgCache),
-		SentTgAlarms:   make(map[string]alertMsgCache),
-		SentDiAlarms:   make(map[string]alertMsgCache),
-		SentSlkAlarms:  make(map[string]alertMsgCache),
-		SentWHAlarms:   make(map[string]alertMsgCache),
-		AllAlarms:      make(map[string]map[string]alertMsgCache),
-		flappingAlarms: make(map[string]map[string]alertMsgCache),
-		notifyMux:      sync.RWMutex{},
-	}
-	originalAlarms := alarms
-	alarms = testAlarms
-	defer func() { alarms = originalAlarms }()
+	testAlarms := newTestAlarmCache(t)
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Reset alarms for each test
-			testAlarms.SentWHAlarms = make(map[string]alertMsgCache)
+			testAlarms.Sent = make(map[string]map[string]alertMsgCache)
 
 			// Run optional alarm setup (e.g., to set up existing alerts for resolved message tests)
 			if tt.setupAlarms != nil {
@@ -660,7 +900,7 @@ func TestNotifyWebhook(t *testing.T) {
 			// Track whether server was called
 			serverCalled := false
 
-			if tt.msg.wh {
+			if len(tt.msg.notifiers) > 0 {
 				originalTransport := http.DefaultTransport
 				// Avoid binding a local port; simulate webhook responses via a mock transport.
 				http.DefaultTransport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
@@ -675,10 +915,13 @@ func TestNotifyWebhook(t *testing.T) {
 				t.Cleanup(func() {
 					http.DefaultTransport = originalTransport
 				})
-				tt.msg.whURL = "http://webhook.test.local/"
+				tt.msg.alertConfig.Webhook.URL = "http://webhook.test.local/"
 			}
 
-			err := notifyWebhook(tt.msg)
+			var err error
+			for _, n := range tt.msg.notifiers {
+				err = deliver(tt.msg, n)
+			}
 			if tt.expectError && err == nil {
 				t.Errorf("Expected error but got none")
 			}
@@ -697,6 +940,329 @@ func TestNotifyWebhook(t *testing.T) {
 	}
 }
 
+func TestWebhookPayloadDetails(t *testing.T) {
+	var payload WebhookPayload
+	originalTransport := http.DefaultTransport
+	http.DefaultTransport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			t.Error(err)
+		}
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewBuffer(nil)), Header: make(http.Header), Request: req}, nil
+	})
+	defer func() { http.DefaultTransport = originalTransport }()
+
+	msg := &alertMsg{
+		chain:        "test-chain (test-chain-1)",
+		chainId:      "test-chain-1",
+		message:      "RPC node tcp://a has been down for > 3 minutes",
+		severity:     "critical",
+		uniqueId:     "RPCNodeDown_testval123_tcp://a",
+		alertDetails: alertDetails{kind: kindRPCNodeDown, labels: map[string]string{"node": "tcp://a", "severity": "info"}, value: 4.5, threshold: 3},
+		alertConfig:  &AlertConfig{Webhook: WebhookConfig{URL: "http://webhook.test.local/"}},
+	}
+	if err := (webhookNotifier{}).Send(msg); err != nil {
+		t.Fatal(err)
+	}
+	a := payload.Alerts[0]
+	if a.Labels["alert_kind"] != "RPCNodeDown" || a.Labels["node"] != "tcp://a" || a.Labels["chain_id"] != "test-chain-1" {
+		t.Errorf("unexpected labels %v", a.Labels)
+	}
+	if a.Labels["severity"] != "critical" {
+		t.Error("the alert's labels should not replace the standard ones")
+	}
+	if a.Annotations["value"] != "4.5" || a.Annotations["threshold"] != "3" {
+		t.Errorf("unexpected annotations %v", a.Annotations)
+	}
+
+	payload, msg.alertDetails = WebhookPayload{}, alertDetails{kind: kindValidatorInactive}
+	if err := (webhookNotifier{}).Send(msg); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := payload.Alerts[0].Annotations["value"]; ok {
+		t.Error("alerts that are not numeric should not have a value")
+	}
+
+	details := pagerdutyDetails(&alertMsg{
+		configName:   "test-chain",
+		alertDetails: alertDetails{kind: kindStakeChange, labels: map[string]string{"trend": "dropped"}, value: 12, threshold: 10},
+		alertConfig:  &AlertConfig{Pagerduty: PDConfig{Priorities: map[string]string{"StakeChange": "P1"}}},
+	})
+	if details["kind"] != kindStakeChange || details["value"] != 12.0 || details["threshold"] != 10.0 || details["labels"].(map[string]string)["trend"] != "dropped" ||
+		details["priority"] != "P1" {
+		t.Errorf("unexpected pagerduty details %v", details)
+	}
+}
+
+func TestNotifyOpsgenie(t *testing.T) {
+	newTestAlarmCache(t)
+
+	var requests []*http.Request
+	var bodies [][]byte
+	originalTransport := http.DefaultTransport
+	http.DefaultTransport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		b, _ := io.ReadAll(req.Body)
+		requests = append(requests, req)
+		bodies = append(bodies, b)
+		return &http.Response{
+			StatusCode: 202,
+			Body:       io.NopCloser(bytes.NewBuffer(nil)),
+			Header:     make(http.Header),
+			Request:    req,
+		}, nil
+	})
+	defer func() { http.DefaultTransport = originalTransport }()
+
+	msg := &alertMsg{
+		chain:          "test-chain (test-1)",
+		message:        "validator has missed 5 blocks",
+		severity:       "critical",
+		uniqueId:       "ConsecutiveBlocksMissed_testval123",
+		valoperAddress: "testval123",
+		valconsAddress: "testvalcons",
+		alertConfig: &AlertConfig{
+			Opsgenie: OpsgenieConfig{
+				ApiKey:            "og-key",
+				SeverityThreshold: "warning",
+			},
+		},
+	}
+
+	if err := deliver(msg, opsgenieNotifier{}); err != nil {
+		t.Fatalf("unexpected error creating alert: %v", err)
+	}
+	msg.resolved = true
+	if err := deliver(msg, opsgenieNotifier{}); err != nil {
+		t.Fatalf("unexpected error closing alert: %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+
+	if requests[0].URL.String() != "https://api.opsgenie.com/v2/alerts" {
+		t.Errorf("unexpected create URL %s", requests[0].URL)
+	}
+	if requests[0].Header.Get("Authorization") != "GenieKey og-key" {
+		t.Errorf("unexpected authorization header %s", requests[0].Header.Get("Authorization"))
+	}
+	created := &OpsgenieAlert{}
+	if err := json.Unmarshal(bodies[0], created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Alias != msg.uniqueId || created.Priority != "P1" || created.Details["valcons_address"] != "testvalcons" {
+		t.Errorf("unexpected create payload %+v", created)
+	}
+
+	if requests[1].URL.String() != "https://api.opsgenie.com/v2/alerts/ConsecutiveBlocksMissed_testval123/close?identifierType=alias" {
+		t.Errorf("unexpected close URL %s", requests[1].URL)
+	}
+
+	// warnings below the threshold are not sent
+	msg.resolved = false
+	msg.severity = "info"
+	msg.uniqueId = "StakeChange_testval123"
+	if err := deliver(msg, opsgenieNotifier{}); err != nil || len(requests) != 2 {
+		t.Errorf("expected info alert to be filtered by the severity threshold, err: %v", err)
+	}
+}
+
+func TestNotifyAlertmanager(t *testing.T) {
+	newTestAlarmCache(t)
+
+	var requests []*http.Request
+	var bodies [][]byte
+	originalTransport := http.DefaultTransport
+	http.DefaultTransport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		b, _ := io.ReadAll(req.Body)
+		requests = append(requests, req)
+		bodies = append(bodies, b)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewBuffer(nil)),
+			Header:     make(http.Header),
+			Request:    req,
+		}, nil
+	})
+	defer func() { http.DefaultTransport = originalTransport }()
+
+	msg := &alertMsg{
+		chain:          "test-chain (test-1)",
+		message:        "validator has missed 5 blocks",
+		severity:       "critical",
+		uniqueId:       "ConsecutiveBlocksMissed_testval123",
+		valoperAddress: "testval123",
+		alertConfig: &AlertConfig{
+			Alertmanager: AlertmanagerConfig{
+				URL:    "http://alertmanager.test.local:9093/",
+				Labels: map[string]string{"team": "validators"},
+			},
+		},
+	}
+
+	if err := deliver(msg, alertmanagerNotifier{}); err != nil {
+		t.Fatalf("unexpected error posting alert: %v", err)
+	}
+	msg.resolved = true
+	if err := deliver(msg, alertmanagerNotifier{}); err != nil {
+		t.Fatalf("unexpected error resolving alert: %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+
+	var firing, resolved []AlertmanagerAlert
+	if err := json.Unmarshal(bodies[0], &firing); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(bodies[1], &resolved); err != nil {
+		t.Fatal(err)
+	}
+	if requests[0].URL.String() != "http://alertmanager.test.local:9093/api/v2/alerts" {
+		t.Errorf("unexpected URL %s", requests[0].URL)
+	}
+	if !reflect.DeepEqual(firing[0].Labels, resolved[0].Labels) {
+		t.Errorf("firing and resolved labels must match: %v != %v", firing[0].Labels, resolved[0].Labels)
+	}
+	if firing[0].Labels["team"] != "validators" || firing[0].Labels["alertname"] != msg.uniqueId {
+		t.Errorf("unexpected labels %v", firing[0].Labels)
+	}
+	endsAt, err := time.Parse(time.RFC3339, firing[0].EndsAt)
+	if err != nil || !endsAt.After(time.Now().Add(time.Hour)) {
+		t.Errorf("expected firing alert to stay open, endsAt: %s", firing[0].EndsAt)
+	}
+	endsAt, err = time.Parse(time.RFC3339, resolved[0].EndsAt)
+	if err != nil || endsAt.After(time.Now()) {
+		t.Errorf("expected resolved alert to end now, endsAt: %s", resolved[0].EndsAt)
+	}
+}
+
+// smtpSink is a minimal SMTP server speaking over one side of a net.Pipe, it records the envelope and data.
+type smtpSink struct {
+	from string
+	to   []string
+	data string
+}
+
+func (s *smtpSink) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+	reply("220 sink ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 sink")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			s.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.to = append(s.to, strings.Trim(line[len("RCPT TO:"):], "<>"))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			data := &strings.Builder{}
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.data = data.String()
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestNotifyEmail(t *testing.T) {
+	newTestAlarmCache(t)
+
+	sink := &smtpSink{}
+	dialed := ""
+	originalDial := smtpDial
+	smtpDial = func(addr string, implicitTLS bool, _ *tls.Config) (net.Conn, error) {
+		dialed = addr
+		if implicitTLS {
+			t.Errorf("expected a plain connection to the sink")
+		}
+		client, server := net.Pipe()
+		go sink.serve(server)
+		return client, nil
+	}
+	defer func() { smtpDial = originalDial }()
+
+	msg := &alertMsg{
+		notifiers:      []Notifier{emailNotifier{}},
+		chain:          "test-chain (test-1)",
+		message:        "validator has missed 5 blocks",
+		severity:       "critical",
+		uniqueId:       "ConsecutiveBlocksMissed_testval123",
+		valoperAddress: "testval123",
+		alertConfig: &AlertConfig{
+			Email: EmailConfig{
+				Host:              "localhost",
+				Port:              1025,
+				TLS:               "none",
+				From:              "tenderduty@example.com",
+				To:                []string{"oncall@example.com", "ops@example.com"},
+				SeverityThreshold: "warning",
+			},
+		},
+	}
+
+	if err := deliver(msg, emailNotifier{}); err != nil {
+		t.Fatalf("unexpected error sending email: %v", err)
+	}
+	if dialed != "localhost:1025" {
+		t.Errorf("expected to dial localhost:1025, got %s", dialed)
+	}
+	if sink.from != "tenderduty@example.com" {
+		t.Errorf("unexpected envelope sender %s", sink.from)
+	}
+	if !reflect.DeepEqual(sink.to, []string{"oncall@example.com", "ops@example.com"}) {
+		t.Errorf("unexpected envelope recipients %v", sink.to)
+	}
+	if !strings.Contains(sink.data, "validator has missed 5 blocks") || !strings.Contains(sink.data, "Alert ID: ConsecutiveBlocksMissed_testval123") {
+		t.Errorf("message body missing alert details: %s", sink.data)
+	}
+
+	// a duplicate is suppressed by shouldNotify, the resolution goes out
+	sink.data = ""
+	if err := deliver(msg, emailNotifier{}); err != nil || sink.data != "" {
+		t.Errorf("expected duplicate alert to be suppressed, err: %v", err)
+	}
+	msg.resolved = true
+	if err := deliver(msg, emailNotifier{}); err != nil {
+		t.Fatalf("unexpected error sending resolution: %v", err)
+	}
+	subject := mime.QEncoding.Encode("utf-8", "TenderDuty 💜 Resolved: test-chain (test-1)")
+	if !strings.Contains(sink.data, "Subject: "+subject) {
+		t.Errorf("expected a resolved subject, got: %s", sink.data)
+	}
+
+	// below the severity threshold nothing is sent
+	sink.data = ""
+	msg.resolved = false
+	msg.severity = "info"
+	msg.uniqueId = "StakeChange_testval123"
+	if err := deliver(msg, emailNotifier{}); err != nil || sink.data != "" {
+		t.Errorf("expected info alert to be filtered by the severity threshold, err: %v", err)
+	}
+}
+
 func TestConfigAlert(t *testing.T) {
 	// Create test config
 	config := &Config{
@@ -750,7 +1316,7 @@ func TestConfigAlert(t *testing.T) {
 	}
 
 	alertID := "test_alert_id"
-	config.alert("test-chain", "test message", "critical", false, &alertID)
+	config.alert("test-chain", "test message", "critical", false, &alertID, alertDetails{})
 
 	// Check that alert was sent to channel
 	select {
@@ -764,29 +1330,50 @@ func TestConfigAlert(t *testing.T) {
 		if alertMsg.uniqueId != "test_alert_id" {
 			t.Errorf("Expected uniqueId 'test_alert_id', got '%s'", alertMsg.uniqueId)
 		}
-		if alertMsg.pd != true {
+		if !alertMsg.notifies("pagerduty") {
 			t.Errorf("Expected pagerduty to be enabled")
 		}
-		if alertMsg.tg != true {
+		if !alertMsg.notifies("telegram") {
 			t.Errorf("Expected telegram to be enabled")
 		}
-		if alertMsg.disc != false {
+		if alertMsg.notifies("discord") {
 			t.Errorf("Expected discord to be disabled")
 		}
-		if alertMsg.slk != false {
+		if alertMsg.notifies("slack") {
 			t.Errorf("Expected slack to be disabled")
 		}
-		if alertMsg.wh != true {
+		if !alertMsg.notifies("webhook") {
 			t.Errorf("Expected webhook to be enabled")
 		}
-		if alertMsg.whURL != "https://test-webhook.example.com" {
-			t.Errorf("Expected webhook URL 'https://test-webhook.example.com', got '%s'", alertMsg.whURL)
+		if alertMsg.alertConfig.Webhook.URL != "https://test-webhook.example.com" {
+			t.Errorf("Expected webhook URL 'https://test-webhook.example.com', got '%s'", alertMsg.alertConfig.Webhook.URL)
 		}
 	case <-time.After(time.Second):
 		t.Error("Alert was not sent to channel")
 	}
 }
 
+func TestConfigAlertSeverityOverride(t *testing.T) {
+	newTestAlarmCache(t)
+	config := createTestConfig()
+	config.Chains["test-chain"].Alerts.Severities = map[string]string{"StakeChange": "critical"}
+
+	id := "StakeChange_testval123"
+	config.alert("test-chain", "stake dropped", "warning", false, &id, alertDetails{kind: kindStakeChange})
+	if msg := <-config.alertChan; msg.severity != "critical" {
+		t.Errorf("expected the configured severity, got %s", msg.severity)
+	}
+	if alarms.AllAlarms["test-chain"][id].Severity != "critical" {
+		t.Error("the configured severity should be kept for reminders")
+	}
+
+	id = "ChainStalled_testval123"
+	config.alert("test-chain", "stalled", "critical", false, &id, alertDetails{})
+	if msg := <-config.alertChan; msg.severity != "critical" || msg.kind != kindChainStalled {
+		t.Errorf("alert types without a severity should keep theirs, got %s %s", msg.kind, msg.severity)
+	}
+}
+
 func TestApplyAlertDefaultsCustom(t *testing.T) {
 	// Create default config
 	defaultConfig := &AlertConfig{
@@ -961,14 +1548,7 @@ func TestChainConfigMkUpdate(t *testing.T) {
 }
 
 func TestEvaluateConsecutiveBlocksMissedAlert(t *testing.T) {
-	// Setup test alarm cache
-	testAlarms := &alarmCache{
-		AllAlarms: make(map[string]map[string]alertMsgCache),
-		notifyMux: sync.RWMutex{},
-	}
-	originalAlarms := alarms
-	alarms = testAlarms
-	defer func() { alarms = originalAlarms }()
+	testAlarms := newTestAlarmCache(t)
 
 	// Setup test td
 	originalTd := td
@@ -1060,14 +1640,7 @@ func TestEvaluateConsecutiveBlocksMissedAlert(t *testing.T) {
 }
 
 func TestEvaluatePercentageBlocksMissedAlert(t *testing.T) {
-	// Setup test alarm cache
-	testAlarms := &alarmCache{
-		AllAlarms: make(map[string]map[string]alertMsgCache),
-		notifyMux: sync.RWMutex{},
-	}
-	originalAlarms := alarms
-	alarms = testAlarms
-	defer func() { alarms = originalAlarms }()
+	testAlarms := newTestAlarmCache(t)
 
 	// Setup test td
 	originalTd := td
@@ -1157,14 +1730,7 @@ func TestEvaluatePercentageBlocksMissedAlert(t *testing.T) {
 }
 
 func TestEvaluateChainStalledAlert(t *testing.T) {
-	// Setup test alarm cache
-	testAlarms := &alarmCache{
-		AllAlarms: make(map[string]map[string]alertMsgCache),
-		notifyMux: sync.RWMutex{},
-	}
-	originalAlarms := alarms
-	alarms = testAlarms
-	defer func() { alarms = originalAlarms }()
+	testAlarms := newTestAlarmCache(t)
 
 	// Setup test td
 	originalTd := td
@@ -1247,14 +1813,7 @@ func TestEvaluateChainStalledAlert(t *testing.T) {
 }
 
 func TestEvaluateValidatorInactiveAlert(t *testing.T) {
-	// Setup test alarm cache
-	testAlarms := &alarmCache{
-		AllAlarms: make(map[string]map[string]alertMsgCache),
-		notifyMux: sync.RWMutex{},
-	}
-	originalAlarms := alarms
-	alarms = testAlarms
-	defer func() { alarms = originalAlarms }()
+	testAlarms := newTestAlarmCache(t)
 
 	// Setup test td
 	originalTd := td
@@ -1341,14 +1900,7 @@ func TestEvaluateValidatorInactiveAlert(t *testing.T) {
 }
 
 func TestEvaluateConsecutiveEmptyBlocksAlert(t *testing.T) {
-	// Setup test alarm cache
-	testAlarms := &alarmCache{
-		AllAlarms: make(map[string]map[string]alertMsgCache),
-		notifyMux: sync.RWMutex{},
-	}
-	originalAlarms := alarms
-	alarms = testAlarms
-	defer func() { alarms = originalAlarms }()
+	testAlarms := newTestAlarmCache(t)
 
 	// Setup test td
 	originalTd := td
@@ -1431,14 +1983,7 @@ func TestEvaluateConsecutiveEmptyBlocksAlert(t *testing.T) {
 }
 
 func TestEvaluatePercentageEmptyBlocksAlert(t *testing.T) {
-	// Setup test alarm cache
-	testAlarms := &alarmCache{
-		AllAlarms: make(map[string]map[string]alertMsgCache),
-		notifyMux: sync.RWMutex{},
-	}
-	originalAlarms := alarms
-	alarms = testAlarms
-	defer func() { alarms = originalAlarms }()
+	testAlarms := newTestAlarmCache(t)
 
 	// Setup test td
 	originalTd := td
@@ -1536,14 +2081,7 @@ func TestEvaluatePercentageEmptyBlocksAlert(t *testing.T) {
 }
 
 func TestEvaluateNoRPCEndpointsAlert(t *testing.T) {
-	// Setup test alarm cache
-	testAlarms := &alarmCache{
-		AllAlarms: make(map[string]map[string]alertMsgCache),
-		notifyMux: sync.RWMutex{},
-	}
-	originalAlarms := alarms
-	alarms = testAlarms
-	defer func() { alarms = originalAlarms }()
+	testAlarms := newTestAlarmCache(t)
 
 	// Setup test td
 	originalTd := td
@@ -1651,14 +2189,7 @@ func TestEvaluateNoRPCEndpointsAlert(t *testing.T) {
 }
 
 func TestEvaluateRPCNodeDownAlert(t *testing.T) {
-	// Setup test alarm cache
-	testAlarms := &alarmCache{
-		AllAlarms: make(map[string]map[string]alertMsgCache),
-		notifyMux: sync.RWMutex{},
-	}
-	originalAlarms := alarms
-	alarms = testAlarms
-	defer func() { alarms = originalAlarms }()
+	testAlarms := newTestAlarmCache(t)
 
 	// Setup test td
 	originalTd := td
@@ -1791,14 +2322,7 @@ func TestEvaluateRPCNodeDownAlert(t *testing.T) {
 }
 
 func TestEvaluateStakeChangeAlert(t *testing.T) {
-	// Setup test alarm cache
-	testAlarms := &alarmCache{
-		AllAlarms: make(map[string]map[string]alertMsgCache),
-		notifyMux: sync.RWMutex{},
-	}
-	originalAlarms := alarms
-	alarms = testAlarms
-	defer func() { alarms = originalAlarms }()
+	testAlarms := newTestAlarmCache(t)
 
 	// Setup test td
 	originalTd := td
@@ -2036,14 +2560,7 @@ func TestEvaluateStakeChangeAlert(t *testing.T) {
 }
 
 func TestEvaluateUnvotedGovernanceProposalAlert(t *testing.T) {
-	// Setup test alarm cache
-	testAlarms := &alarmCache{
-		AllAlarms: make(map[string]map[string]alertMsgCache),
-		notifyMux: sync.RWMutex{},
-	}
-	originalAlarms := alarms
-	alarms = testAlarms
-	defer func() { alarms = originalAlarms }()
+	testAlarms := newTestAlarmCache(t)
 
 	// Setup test td
 	originalTd := td
@@ -2146,6 +2663,12 @@ func TestEvaluateUnvotedGovernanceProposalAlert(t *testing.T) {
 			if resolved != tt.expectedResolved {
 				t.Errorf("%s: expected resolved %v, got %v", tt.description, tt.expectedResolved, resolved)
 			}
+			if alert {
+				cache := testAlarms.AllAlarms["test-chain"]["UnvotedGovernanceProposal_testval123_1"]
+				if cache.Kind != kindUnvotedGovernanceProposal || cache.Labels["proposal_id"] != "1" {
+					t.Errorf("expected the kind and proposal_id label to be kept, got %+v", cache)
+				}
+			}
 		})
 	}
 }
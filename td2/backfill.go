@@ -0,0 +1,41 @@
+package tenderduty
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// maxBackfill is how many missing heights are looked up after the websocket reconnects, the dashboard does not show
+// more than this anyway.
+const maxBackfill = showBLocks
+
+// backfillTimeout limits how long the missing heights are looked up for, new blocks wait until the backfill is done.
+const backfillTimeout = 20 * time.Second
+
+// backfill counts the heights from..to that the websocket did not deliver, so that blocks missed while it was
+// reconnecting are not lost. They are fetched from the healthy nodes like bootstrap does, and heights that cannot be
+// looked up in time are shown as unknown.
+func (cc *ChainConfig) backfill(ctx context.Context, from, to int64) {
+	if n := to - from + 1; n > maxBackfill {
+		l(slog.LevelWarn, fmt.Sprintf("⏪ %-12s %d blocks arrived while reconnecting, only the last %d are looked up", cc.ChainId, n, maxBackfill))
+		from = to - maxBackfill + 1
+	}
+	l(fmt.Sprintf("⏪ %-12s looking up blocks %d to %d that arrived while reconnecting", cc.ChainId, from, to))
+	statuses := make([]StatusType, to-from+1)
+	for i := range statuses {
+		statuses[i] = -1
+	}
+	if nodes := cc.healthyNodes(); cc.valInfo.Bonded && len(nodes) > 0 {
+		fetchCtx, cancel := context.WithTimeout(ctx, backfillTimeout)
+		statuses = cc.fetchStatuses(fetchCtx, newRPCClient(), nodes, from, to)
+		cancel()
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	for i, status := range statuses {
+		cc.countBlock(from+int64(i), status)
+	}
+}
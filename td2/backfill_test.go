@@ -0,0 +1,81 @@
+package tenderduty
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// testBlock is what the backfill reads from a block: its proposer, its number of txs and the validators in its
+// last commit.
+type testBlock struct {
+	proposer string
+	txs      int
+	signers  []string
+}
+
+// newBlockServer serves /block for the given heights, other heights are an error.
+func newBlockServer(t *testing.T, blocks map[int64]testBlock) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		height, _ := strconv.ParseInt(r.URL.Query().Get("height"), 10, 64)
+		b, ok := blocks[height]
+		if r.URL.Path != "/block" || !ok {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":-1,"error":{"code":-32603,"message":"height is not available"}}`))
+			return
+		}
+		txs, signatures := make([]string, b.txs), make([]map[string]string, 0)
+		for i := range txs {
+			txs[i] = "dHg="
+		}
+		for _, signer := range b.signers {
+			signatures = append(signatures, map[string]string{"validator_address": signer})
+		}
+		block := map[string]any{
+			"header":      map[string]string{"height": strconv.FormatInt(height, 10), "proposer_address": b.proposer},
+			"data":        map[string]any{"txs": txs},
+			"last_commit": map[string]any{"signatures": signatures},
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": -1, "result": map[string]any{"block": block}})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestBackfill(t *testing.T) {
+	setupOutboxTest(t)
+	// the validator's signature for a height is in the last commit of that block, like in the live stream
+	server := newBlockServer(t, map[int64]testBlock{
+		11: {proposer: "0123456789ABCDEF", txs: 1},
+		12: {proposer: "0123456789ABCDEF"},
+		13: {proposer: "OTHER", signers: []string{"OTHER", "0123456789ABCDEF"}},
+		14: {proposer: "OTHER", signers: []string{"OTHER"}},
+	})
+
+	cc := td.Chains["test-chain"]
+	cc.valInfo = &ValInfo{Moniker: "testval", Bonded: true, Conspub: []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}}
+	cc.Nodes = []*NodeConfig{{Url: server.URL}, {Url: "http://127.0.0.1:1", down: true}}
+	cc.blocksResults = make([]int, showBLocks)
+
+	// 15 cannot be looked up
+	cc.backfill(context.Background(), 11, 15)
+	expected := []int{-1, int(Statusmissed), int(StatusSigned), int(StatusProposedEmpty), int(StatusProposed)}
+	for i, status := range expected {
+		if cc.blocksResults[i] != status {
+			t.Errorf("expected the grid to start with %v, got %v", expected, cc.blocksResults[:len(expected)])
+			break
+		}
+	}
+	if cc.statTotalSigns != 3 || cc.statTotalProps != 2 || cc.statTotalPropsEmpty != 1 || cc.statTotalMiss != 1 || cc.statConsecutiveMiss != 1 {
+		t.Errorf("unexpected counters: %v signed, %v proposed, %v empty, %v missed, %v in a row",
+			cc.statTotalSigns, cc.statTotalProps, cc.statTotalPropsEmpty, cc.statTotalMiss, cc.statConsecutiveMiss)
+	}
+
+	// a long gap only looks up the most recent heights, which are not available here
+	cc.backfill(context.Background(), 1, maxBackfill+20)
+	if cc.blocksResults[len(cc.blocksResults)-1] != -1 || cc.statTotalSigns != 3 {
+		t.Error("the backfill should be capped")
+	}
+}
@@ -0,0 +1,179 @@
+package tenderduty
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BootstrapConfig fills the dashboard's block grid from the recent blocks at startup, instead of waiting for new ones.
+type BootstrapConfig struct {
+	// Enabled is off unless it is set to yes
+	Enabled bool `yaml:"enabled"`
+	// Concurrency is how many blocks are fetched from each node at the same time, 4 by default
+	Concurrency int `yaml:"concurrency"`
+}
+
+// bootstrapSettings returns the bootstrap settings with the defaults filled in.
+func bootstrapSettings() BootstrapConfig {
+	b := BootstrapConfig{}
+	if td != nil {
+		b = td.Bootstrap
+	}
+	if b.Concurrency <= 0 {
+		b.Concurrency = 4
+	}
+	return b
+}
+
+// rpcGet calls an RPC endpoint of a node with a GET request, and decodes the result.
+func rpcGet(ctx context.Context, client *http.Client, node, path string, result any) error {
+	u, err := url.Parse(strings.TrimRight(node, "/"))
+	if err != nil {
+		return err
+	}
+	if u.Scheme == "tcp" {
+		u.Scheme = "http"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String()+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	reply := struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+			Data    string `json:"data"`
+		} `json:"error"`
+	}{}
+	if err = json.Unmarshal(b, &reply); err != nil {
+		return fmt.Errorf("%s returned %s: %w", path, resp.Status, err)
+	}
+	if reply.Error != nil {
+		return fmt.Errorf("%s: %s %s", path, reply.Error.Message, reply.Error.Data)
+	}
+	return json.Unmarshal(reply.Result, result)
+}
+
+// gridEmpty reports if the dashboard's grid has no blocks, which is the case on a fresh start without a state file.
+func (cc *ChainConfig) gridEmpty() bool {
+	for _, status := range cc.blocksResults {
+		if status != -1 {
+			return false
+		}
+	}
+	return true
+}
+
+// healthyNodes returns the URLs of the nodes that are not down.
+func (cc *ChainConfig) healthyNodes() []string {
+	nodes := make([]string, 0)
+	for _, node := range cc.Nodes {
+		if !node.down {
+			nodes = append(nodes, node.Url)
+		}
+	}
+	return nodes
+}
+
+// newRPCClient returns a client for rpcGet.
+func newRPCClient() *http.Client {
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			//#nosec G402 -- configurable option
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: td.TLSSkipVerify},
+		},
+	}
+}
+
+// fetchStatuses fetches the blocks first..last from the nodes and classifies them like the live stream does. Each node
+// is sent at most Concurrency requests at a time, and the blocks that could not be fetched are -1.
+func (cc *ChainConfig) fetchStatuses(ctx context.Context, client *http.Client, nodes []string, first, last int64) []StatusType {
+	address := strings.ToUpper(hex.EncodeToString(cc.valInfo.Conspub))
+	results := make([]StatusType, last-first+1)
+	limits := make([]chan struct{}, len(nodes))
+	for i := range limits {
+		limits[i] = make(chan struct{}, bootstrapSettings().Concurrency)
+	}
+	wg := sync.WaitGroup{}
+	for i := range results {
+		node, limit := nodes[i%len(nodes)], limits[i%len(nodes)]
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+			results[i] = -1
+			b := &rawBlock{}
+			if err := rpcGet(ctx, client, node, fmt.Sprintf("/block?height=%d", first+int64(i)), b); err != nil {
+				l(slog.LevelDebug, fmt.Sprintf("⏪ %-12s could not fetch block %d: %s", cc.ChainId, first+int64(i), err))
+				return
+			}
+			results[i] = b.status(address)
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// bootstrap fetches the last showBLocks blocks from the healthy nodes and counts them, so that the grid, the
+// consecutive counters and the Prometheus stats are right soon after startup.
+func (cc *ChainConfig) bootstrap(ctx context.Context) error {
+	if !cc.valInfo.Bonded {
+		return errors.New("the validator is not in the active set")
+	}
+	nodes := cc.healthyNodes()
+	if len(nodes) == 0 {
+		return errors.New("no healthy nodes to fetch blocks from")
+	}
+
+	info := struct {
+		SyncInfo struct {
+			LatestBlockHeight stringInt64 `json:"latest_block_height"`
+		} `json:"sync_info"`
+	}{}
+	client := newRPCClient()
+	if err := rpcGet(ctx, client, nodes[0], "/status", &info); err != nil {
+		return err
+	}
+	latest := info.SyncInfo.LatestBlockHeight.val()
+	first := max(latest-showBLocks+1, 1)
+	if latest < first {
+		return errors.New("the chain has no blocks yet")
+	}
+	l(fmt.Sprintf("⏪ %-12s fetching blocks %d to %d for the dashboard", cc.ChainId, first, latest))
+
+	results := cc.fetchStatuses(ctx, client, nodes, first, latest)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	for i, status := range results {
+		cc.countBlock(first+int64(i), status)
+	}
+	cc.lastBlockNum = latest
+	if td.Prom {
+		cc.blockStats(len(nodes))
+	}
+	return nil
+}
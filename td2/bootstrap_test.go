@@ -0,0 +1,80 @@
+package tenderduty
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newChainServer serves /status and /block for a chain at height 600, the validator proposed the last block and
+// missed the one before it. It records the most requests it handled at the same time.
+func newChainServer(t *testing.T, address string, busiest *int32) *httptest.Server {
+	var inFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/status" {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":-1,"result":{"sync_info":{"latest_block_height":"600"}}}`))
+			return
+		}
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			b := atomic.LoadInt32(busiest)
+			if n <= b || atomic.CompareAndSwapInt32(busiest, b, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+
+		height, _ := strconv.Atoi(r.URL.Query().Get("height"))
+		proposer, signatures := "OTHER", fmt.Sprintf(`[{"validator_address":"OTHER"},{"validator_address":"%s"}]`, address)
+		switch height {
+		case 600:
+			proposer = address
+		case 599:
+			signatures = `[{"validator_address":"OTHER"}]`
+		}
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":-1,"result":{"block":{"header":{"height":"%d","proposer_address":"%s"},"data":{"txs":[]},"last_commit":{"signatures":%s}}}}`,
+			height, proposer, signatures)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestBootstrap(t *testing.T) {
+	setupOutboxTest(t)
+	td.Bootstrap = BootstrapConfig{Enabled: true, Concurrency: 2}
+	var busiest1, busiest2 int32
+	node1 := newChainServer(t, "0123456789ABCDEF", &busiest1)
+	node2 := newChainServer(t, "0123456789ABCDEF", &busiest2)
+
+	cc := td.Chains["test-chain"]
+	cc.valInfo = &ValInfo{Moniker: "testval", Bonded: true, Conspub: []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}}
+	cc.Nodes = []*NodeConfig{{Url: node1.URL}, {Url: node2.URL}, {Url: "http://127.0.0.1:1", down: true}}
+	cc.blocksResults = make([]int, showBLocks)
+	for i := range cc.blocksResults {
+		cc.blocksResults[i] = -1
+	}
+	if !cc.gridEmpty() {
+		t.Fatal("the grid should be empty")
+	}
+
+	if err := cc.bootstrap(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if cc.blocksResults[0] != int(StatusProposedEmpty) || cc.blocksResults[1] != int(Statusmissed) || cc.blocksResults[2] != int(StatusSigned) ||
+		cc.blocksResults[showBLocks-1] != int(StatusSigned) || cc.gridEmpty() {
+		t.Errorf("unexpected grid %v", cc.blocksResults[:3])
+	}
+	if cc.statTotalSigns != showBLocks-1 || cc.statTotalMiss != 1 || cc.statConsecutiveMiss != 0 || cc.statConsecutiveEmpty != 1 || cc.lastBlockNum != 600 {
+		t.Errorf("unexpected counters: %v signed, %v missed, %v in a row, %v empty in a row, last block %d",
+			cc.statTotalSigns, cc.statTotalMiss, cc.statConsecutiveMiss, cc.statConsecutiveEmpty, cc.lastBlockNum)
+	}
+	if busiest1 == 0 || busiest2 == 0 || busiest1 > 2 || busiest2 > 2 {
+		t.Errorf("expected at most 2 requests at a time to each node, got %d and %d", busiest1, busiest2)
+	}
+}
@@ -0,0 +1,119 @@
+package tenderduty
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a standard five field cron expression: minute, hour, day of month, month and day of week.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// when both days are restricted a day matching either runs, like cron does
+	domAny, dowAny bool
+}
+
+var cronDescriptors = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+}
+
+// parseCron parses a cron expression, fields can be *, numbers, ranges such as 1-5, lists and steps such as */15.
+// Days of the week are 0 to 7, both 0 and 7 are Sunday.
+func parseCron(spec string) (*cronSchedule, error) {
+	if d, ok := cronDescriptors[strings.TrimSpace(spec)]; ok {
+		spec = d
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron schedule %q must have 5 fields", spec)
+	}
+	s := &cronSchedule{domAny: fields[2] == "*", dowAny: fields[4] == "*"}
+	var err error
+	for i, f := range []struct {
+		bits     *uint64
+		min, max int
+	}{{&s.minute, 0, 59}, {&s.hour, 0, 23}, {&s.dom, 1, 31}, {&s.month, 1, 12}, {&s.dow, 0, 7}} {
+		if *f.bits, err = parseCronField(fields[i], f.min, f.max); err != nil {
+			return nil, fmt.Errorf("cron schedule %q: %w", spec, err)
+		}
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rng = part[:i]
+		}
+		lo, hi := min, max
+		if rng != "*" {
+			bounds := strings.SplitN(rng, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid value %q", part)
+				}
+			} else if step > 1 {
+				// 5/15 means from 5 to the end in steps of 15
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<t.Day()) != 0
+	dow := s.dow&(1<<int(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// next returns the first time after t that the schedule runs, in t's location. It returns the zero time if the
+// schedule never runs, for example on February 30th.
+func (s *cronSchedule) next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<int(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<t.Hour()) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
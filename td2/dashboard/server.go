@@ -170,3 +170,11 @@ func (ch CacheHandler) ServeHTTP(writer http.ResponseWriter, request *http.Reque
 		http.FileServer(http.FS(rootDir)).ServeHTTP(writer, request)
 	}
 }
+
+// HandleAPI registers a handler for an API endpoint on the dashboard server, it must be called before Serve.
+func HandleAPI(pattern string, handler http.HandlerFunc) {
+	http.HandleFunc(pattern, func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Cache-Control", "no-store")
+		handler(writer, request)
+	})
+}
@@ -20,6 +20,7 @@ type ChainStatus struct {
 	Nodes                   int                                          `json:"nodes"`
 	HealthyNodes            int                                          `json:"healthy_nodes"`
 	ActiveAlerts            int                                          `json:"active_alerts"`
+	PendingAlerts           int                                          `json:"pending_alerts"`
 	Height                  int64                                        `json:"height"`
 	LastError               string                                       `json:"last_error"`
 	UnvotedOpenGovProposals int                                          `json:"unvoted_open_gov_proposals"`
@@ -0,0 +1,374 @@
+package tenderduty
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DigestConfig sends a summary of the chains on a schedule, for example a daily report to Slack. It is sent even when
+// nothing is wrong, so that a missing digest is noticed.
+type DigestConfig struct {
+	// Name is shown in the title of the report, for example Daily or Weekly
+	Name string `yaml:"name"`
+	// Schedule is a cron expression, for example "0 9 * * 1" for 09:00 on Mondays, or @daily
+	Schedule string `yaml:"schedule"`
+	// Timezone of the schedule, for example Europe/Berlin, UTC by default
+	Timezone string `yaml:"timezone"`
+	// Destinations can be slack, discord, email and webhook, they use the settings from default_alert_config
+	Destinations []string `yaml:"destinations"`
+	// Chains limits the report to some chains, all chains by default
+	Chains []string `yaml:"chains"`
+}
+
+// reporter is optionally implemented by a Notifier that can deliver digest reports.
+type reporter interface {
+	sendReport(report *DigestReport, cfg *AlertConfig) error
+}
+
+// DigestReport is what a digest sends, the webhook receives it as JSON.
+type DigestReport struct {
+	Type   string        `json:"type"`
+	Name   string        `json:"name"`
+	Start  time.Time     `json:"start"`
+	End    time.Time     `json:"end"`
+	Chains []DigestChain `json:"chains"`
+}
+
+// DigestChain is the summary of one chain. The block counts and alerts are for the period of the report.
+type DigestChain struct {
+	Chain               string  `json:"chain"`
+	ChainID             string  `json:"chain_id"`
+	Moniker             string  `json:"moniker"`
+	Signed              int     `json:"signed"`
+	Missed              int     `json:"missed"`
+	Proposed            int     `json:"proposed"`
+	Empty               int     `json:"empty"`
+	WindowMissedPercent float64 `json:"window_missed_percent"`
+	VotingPowerPercent  float64 `json:"voting_power_percent"`
+	// Rank is the position by voting power, 0 when it is not known. RankChange is positive when the validator moved up.
+	Rank              int     `json:"rank,omitempty"`
+	RankChange        int     `json:"rank_change"`
+	CommissionPercent float64 `json:"commission_percent"`
+	// UnclaimedRewards is in Currency, it is only set when convert_to_fiat is enabled
+	UnclaimedRewards *float64 `json:"unclaimed_rewards,omitempty"`
+	Currency         string   `json:"currency,omitempty"`
+	UnvotedProposals []uint64 `json:"unvoted_proposals"`
+	AlertsFired      int      `json:"alerts_fired"`
+	ActiveAlerts     int      `json:"active_alerts"`
+	// AlertSeconds is the total time alerts were active during the period
+	AlertSeconds float64 `json:"alert_seconds"`
+}
+
+// digestCounters are the block and alert counters when the previous report was made, the reports show the
+// difference.
+type digestCounters struct {
+	signed, missed, proposed, empty float64
+	rank                            int
+	fired                           int
+	alertSeconds                    float64
+}
+
+// digest is a scheduled report and what it needs to remember between runs.
+type digest struct {
+	cfg      DigestConfig
+	schedule *cronSchedule
+	loc      *time.Location
+	last     time.Time
+	previous map[string]digestCounters
+}
+
+func newDigest(cfg DigestConfig) (*digest, error) {
+	schedule, err := parseCron(cfg.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Name == "" {
+		cfg.Name = cfg.Schedule
+	}
+	loc := time.UTC
+	if cfg.Timezone != "" {
+		if loc, err = time.LoadLocation(cfg.Timezone); err != nil {
+			return nil, err
+		}
+	}
+	return &digest{cfg: cfg, schedule: schedule, loc: loc, last: time.Now(), previous: make(map[string]digestCounters)}, nil
+}
+
+// runDigests sends each digest on its schedule until the context is cancelled.
+func (c *Config) runDigests(ctx context.Context) {
+	for i := range c.Digests {
+		d, err := newDigest(c.Digests[i])
+		if err != nil {
+			// reported by validateConfig
+			continue
+		}
+		d.countAlertsFrom(c)
+		go func() {
+			for {
+				next := d.schedule.next(time.Now().In(d.loc))
+				if next.IsZero() {
+					return
+				}
+				timer := time.NewTimer(time.Until(next))
+				select {
+				case <-timer.C:
+					d.send(c.report(ctx, d))
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				}
+			}
+		}()
+	}
+}
+
+// countAlertsFrom remembers the alert counters of the chains, so that the first report does not count the time that
+// alerts restored from the state file were active before the digest started.
+func (d *digest) countAlertsFrom(c *Config) {
+	c.chainsMux.RLock()
+	defer c.chainsMux.RUnlock()
+	for name := range c.Chains {
+		fired, _, seconds := alarms.alertTotals(name, d.last)
+		d.previous[name] = digestCounters{fired: fired, alertSeconds: seconds}
+	}
+}
+
+// report summarizes the chains since the previous report, or since tenderduty started.
+func (c *Config) report(ctx context.Context, d *digest) *DigestReport {
+	r := &DigestReport{Type: "digest", Name: d.cfg.Name, Start: d.last, End: time.Now(), Chains: make([]DigestChain, 0)}
+	d.last = r.End
+
+	c.chainsMux.RLock()
+	names := make([]string, 0, len(c.Chains))
+	for name := range c.Chains {
+		if len(d.cfg.Chains) == 0 || slices.Contains(d.cfg.Chains, name) {
+			names = append(names, name)
+		}
+	}
+	c.chainsMux.RUnlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		c.chainsMux.RLock()
+		cc := c.Chains[name]
+		c.chainsMux.RUnlock()
+		if cc == nil {
+			continue
+		}
+		r.Chains = append(r.Chains, c.chainDigest(ctx, d, cc, r.End))
+	}
+	return r
+}
+
+func (c *Config) chainDigest(ctx context.Context, d *digest, cc *ChainConfig, end time.Time) DigestChain {
+	prev := d.previous[cc.name]
+	now := digestCounters{signed: cc.statTotalSigns, missed: cc.statTotalMiss, proposed: cc.statTotalProps, empty: cc.statTotalPropsEmpty}
+	// the block counters start over when tenderduty restarts
+	if now.signed < prev.signed {
+		prev = digestCounters{rank: prev.rank, fired: prev.fired, alertSeconds: prev.alertSeconds}
+	}
+	dc := DigestChain{
+		Chain:            cc.name,
+		ChainID:          cc.ChainId,
+		Signed:           int(now.signed - prev.signed),
+		Missed:           int(now.missed - prev.missed),
+		Proposed:         int(now.proposed - prev.proposed),
+		Empty:            int(now.empty - prev.empty),
+		UnvotedProposals: make([]uint64, 0),
+	}
+	if cc.valInfo != nil {
+		dc.Moniker = cc.valInfo.Moniker
+		if cc.valInfo.Window > 0 {
+			dc.WindowMissedPercent = 100 * float64(cc.valInfo.Missed) / float64(cc.valInfo.Window)
+		}
+		dc.VotingPowerPercent = 100 * cc.valInfo.VotingPowerPercent
+		dc.CommissionPercent = 100 * cc.valInfo.CommissionRate
+	}
+	if rank, err := cc.votingPowerRank(ctx); err == nil {
+		dc.Rank = rank
+		if prev.rank > 0 && rank > 0 {
+			dc.RankChange = prev.rank - rank
+		}
+		now.rank = rank
+	} else {
+		l(slog.LevelDebug, fmt.Sprintf("could not find the voting power rank on %s: %s", cc.name, err))
+		now.rank = prev.rank
+	}
+	if c.PriceConversion.Enabled {
+		if rewards, ok := cc.unclaimedRewardsInFiat(); ok {
+			dc.UnclaimedRewards = &rewards
+			dc.Currency = c.PriceConversion.Currency
+		}
+	}
+	for _, p := range cc.unvotedOpenGovProposals {
+		dc.UnvotedProposals = append(dc.UnvotedProposals, p.ProposalId)
+	}
+	now.fired, dc.ActiveAlerts, now.alertSeconds = alarms.alertTotals(cc.name, end)
+	dc.AlertsFired = now.fired - prev.fired
+	dc.AlertSeconds = now.alertSeconds - prev.alertSeconds
+	d.previous[cc.name] = now
+	return dc
+}
+
+// votingPowerRank finds the validator's position in the active set, 0 means it is not in the active set.
+func (cc *ChainConfig) votingPowerRank(ctx context.Context) (int, error) {
+	if cc.client == nil || cc.valInfo == nil || cc.valInfo.Conspub == nil {
+		return 0, fmt.Errorf("%s is not connected", cc.name)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	address := strings.ToUpper(hex.EncodeToString(cc.valInfo.Conspub))
+	perPage := 100
+	for page, seen := 1, 0; ; page++ {
+		result, err := cc.client.Validators(ctx, nil, &page, &perPage)
+		if err != nil {
+			return 0, err
+		}
+		for i, v := range result.Validators {
+			if v.Address.String() == address {
+				return seen + i + 1, nil
+			}
+		}
+		seen += len(result.Validators)
+		if len(result.Validators) == 0 || seen >= result.Total {
+			return 0, nil
+		}
+	}
+}
+
+// send delivers a report to the digest's destinations, failures are logged.
+func (d *digest) send(r *DigestReport) {
+	for _, name := range d.cfg.Destinations {
+		n, _ := getNotifier(name)
+		rep, ok := n.(reporter)
+		if !ok {
+			continue
+		}
+		if err := rep.sendReport(r, &td.DefaultAlertConfig); err != nil {
+			l(slog.LevelWarn, fmt.Sprintf("⚠️ could not send the %s digest to %s: %s", d.cfg.Name, name, err))
+			continue
+		}
+		l(slog.LevelInfo, fmt.Sprintf("📰 sent the %s digest to %s", d.cfg.Name, name))
+	}
+}
+
+// postJSON posts a report to a webhook URL.
+func postJSON(url string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("got %d response", resp.StatusCode)
+	}
+	return nil
+}
+
+// title is the heading of a report.
+func (r *DigestReport) title() string {
+	return fmt.Sprintf("TenderDuty %s digest, %s to %s", r.Name, r.Start.UTC().Format("2006-01-02 15:04"), r.End.UTC().Format("2006-01-02 15:04 MST"))
+}
+
+// text is the summary of a chain as lines of plain text.
+func (dc *DigestChain) text() string {
+	lines := []string{
+		fmt.Sprintf("Blocks: %d signed, %d missed, %d proposed, %d empty", dc.Signed, dc.Missed, dc.Proposed, dc.Empty),
+		fmt.Sprintf("Window: %.2f%% missed", dc.WindowMissedPercent),
+	}
+	vp := fmt.Sprintf("Voting power: %.2f%%", dc.VotingPowerPercent)
+	if dc.Rank > 0 {
+		vp += fmt.Sprintf(", rank %d", dc.Rank)
+		switch {
+		case dc.RankChange > 0:
+			vp += fmt.Sprintf(" (up %d)", dc.RankChange)
+		case dc.RankChange < 0:
+			vp += fmt.Sprintf(" (down %d)", -dc.RankChange)
+		}
+	}
+	lines = append(lines, vp, fmt.Sprintf("Commission: %.2f%%", dc.CommissionPercent))
+	if dc.UnclaimedRewards != nil {
+		lines = append(lines, fmt.Sprintf("Unclaimed rewards: %.2f %s", *dc.UnclaimedRewards, dc.Currency))
+	}
+	unvoted := "none"
+	if len(dc.UnvotedProposals) > 0 {
+		ids := make([]string, len(dc.UnvotedProposals))
+		for i, id := range dc.UnvotedProposals {
+			ids[i] = fmt.Sprintf("#%d", id)
+		}
+		unvoted = strings.Join(ids, ", ")
+	}
+	lines = append(lines,
+		"Unvoted proposals: "+unvoted,
+		fmt.Sprintf("Alerts: %d fired, %d active, %s in alert", dc.AlertsFired, dc.ActiveAlerts, (time.Duration(dc.AlertSeconds)*time.Second).Round(time.Second)),
+	)
+	return strings.Join(lines, "\n")
+}
+
+// heading names a chain in a report.
+func (dc *DigestChain) heading() string {
+	if dc.Moniker == "" {
+		return fmt.Sprintf("%s (%s)", dc.Chain, dc.ChainID)
+	}
+	return fmt.Sprintf("%s (%s) - %s", dc.Chain, dc.ChainID, dc.Moniker)
+}
+
+// text is the whole report as plain text.
+func (r *DigestReport) text() string {
+	b := &strings.Builder{}
+	b.WriteString(r.title() + "\n")
+	if len(r.Chains) == 0 {
+		b.WriteString("\nNo chains are monitored.\n")
+	}
+	for i := range r.Chains {
+		b.WriteString("\n" + r.Chains[i].heading() + "\n" + r.Chains[i].text() + "\n")
+	}
+	return b.String()
+}
+
+// validateDigests checks the digests in the config file.
+func validateDigests(c *Config) (fatal bool, problems []string) {
+	for i, cfg := range c.Digests {
+		name := cfg.Name
+		if name == "" {
+			name = fmt.Sprintf("%d", i+1)
+		}
+		if _, err := newDigest(cfg); err != nil {
+			fatal = true
+			problems = append(problems, fmt.Sprintf("error: digest %s: %s", name, err))
+		}
+		if len(cfg.Destinations) == 0 {
+			fatal = true
+			problems = append(problems, fmt.Sprintf("error: digest %s has no destinations", name))
+		}
+		for _, dest := range cfg.Destinations {
+			n, ok := getNotifier(dest)
+			if _, canReport := n.(reporter); !ok || !canReport {
+				fatal = true
+				problems = append(problems, fmt.Sprintf("error: digest %s cannot be sent to %s", name, dest))
+			} else if !n.Enabled(&c.DefaultAlertConfig) {
+				problems = append(problems, fmt.Sprintf("warning: digest %s is sent to %s, which is not enabled in default_alert_config", name, dest))
+			}
+		}
+		for _, chain := range cfg.Chains {
+			if c.Chains[chain] == nil {
+				problems = append(problems, fmt.Sprintf("warning: digest %s has an unknown chain %s", name, chain))
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,167 @@
+package tenderduty
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gov "github.com/cosmos/cosmos-sdk/x/gov/types"
+	"github.com/go-yaml/yaml"
+)
+
+func TestCronNext(t *testing.T) {
+	from := time.Date(2026, 10, 14, 10, 30, 0, 0, time.UTC) // a Wednesday
+	tests := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"0 9 * * *", time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1", time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 10, 14, 10, 45, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2026, 10, 15, 10, 30, 0, 0, time.UTC)},
+		{"0 8-17/3 * * 1-5", time.Date(2026, 10, 14, 11, 0, 0, 0, time.UTC)},
+		{"0 0 1 1,7 *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// either day matches when both are set
+		{"0 0 20 * 5", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		s, err := parseCron(tt.spec)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.spec, err)
+		}
+		if got := s.next(from); !got.Equal(tt.expected) {
+			t.Errorf("%s: expected %s, got %s", tt.spec, tt.expected, got)
+		}
+	}
+
+	for _, spec := range []string{"", "0 9 * *", "60 * * * *", "0 0 0 * *", "*/0 * * * *", "a * * * *", "5-1 * * * *"} {
+		if _, err := parseCron(spec); err == nil {
+			t.Errorf("%q should not parse", spec)
+		}
+	}
+}
+
+func TestDigestReport(t *testing.T) {
+	// the alerts are counted without the alert history
+	setupOutboxTest(t)
+	newTestAlarmCache(t)
+	cc := td.Chains["test-chain"]
+	cc.valInfo = &ValInfo{Moniker: "testval", Missed: 50, Window: 10000, VotingPowerPercent: 0.0123, CommissionRate: 0.05}
+	cc.statTotalSigns, cc.statTotalMiss, cc.statTotalProps, cc.statTotalPropsEmpty = 100, 3, 2, 1
+	cc.unvotedOpenGovProposals = []gov.Proposal{{ProposalId: 812}}
+
+	d, err := newDigest(DigestConfig{Name: "Daily", Schedule: "@daily", Destinations: []string{"webhook"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.last = time.Now().Add(-time.Hour)
+
+	// an alert restored from the state file was already active when the digest started
+	alarms.AllAlarms["test-chain"] = map[string]alertMsgCache{"ChainStalled_testval123": {SentTime: time.Now().Add(-2 * time.Hour)}}
+	d.countAlertsFrom(td)
+
+	// another alert fired and resolved after 10 minutes during the period
+	id := "RPCNodeDown_testval123_tcp://a"
+	td.alert("test-chain", "node down", "critical", false, &id, alertDetails{})
+	<-td.alertChan
+	cache := alarms.AllAlarms["test-chain"][id]
+	cache.SentTime = time.Now().Add(-10 * time.Minute)
+	alarms.AllAlarms["test-chain"][id] = cache
+	td.alert("test-chain", "node down", "critical", true, &id, alertDetails{})
+	<-td.alertChan
+
+	r := td.report(td.ctx, d)
+	if len(r.Chains) != 1 {
+		t.Fatalf("expected 1 chain, got %d", len(r.Chains))
+	}
+	dc := r.Chains[0]
+	if dc.Signed != 100 || dc.Missed != 3 || dc.Proposed != 2 || dc.Empty != 1 || dc.WindowMissedPercent != 0.5 ||
+		dc.CommissionPercent != 5 || len(dc.UnvotedProposals) != 1 || dc.AlertsFired != 1 || dc.ActiveAlerts != 1 {
+		t.Errorf("unexpected summary %+v", dc)
+	}
+	// 10 minutes resolved, and the active alert for the hour of the period
+	if dc.AlertSeconds < 4190 || dc.AlertSeconds > 4210 {
+		t.Errorf("expected about 70 minutes in alert, got %f seconds", dc.AlertSeconds)
+	}
+	text := r.text()
+	for _, want := range []string{"TenderDuty Daily digest", "test-chain (test-chain-1) - testval", "Blocks: 100 signed, 3 missed, 2 proposed, 1 empty",
+		"Window: 0.50% missed", "Voting power: 1.23%", "Commission: 5.00%", "Unvoted proposals: #812", "Alerts: 1 fired, 1 active"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("report should contain %q:\n%s", want, text)
+		}
+	}
+
+	// the next report only counts the blocks and alerts since this one
+	cc.statTotalSigns, cc.statTotalMiss = 150, 4
+	if dc = td.report(td.ctx, d).Chains[0]; dc.Signed != 50 || dc.Missed != 1 || dc.Proposed != 0 || dc.AlertsFired != 0 || dc.ActiveAlerts != 1 {
+		t.Errorf("expected the difference since the previous report, got %+v", dc)
+	}
+	if dc.AlertSeconds > 10 {
+		t.Errorf("only the active alert should count since the previous report, got %f seconds", dc.AlertSeconds)
+	}
+}
+
+func TestDigestSendToWebhook(t *testing.T) {
+	setupOutboxTest(t)
+	var got DigestReport
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer server.Close()
+	td.DefaultAlertConfig.Webhook.URL = server.URL
+
+	d, _ := newDigest(DigestConfig{Name: "Weekly", Schedule: "@weekly", Destinations: []string{"webhook"}})
+	d.send(&DigestReport{Type: "digest", Name: "Weekly", Chains: []DigestChain{{Chain: "test-chain"}}})
+	if got.Type != "digest" || got.Name != "Weekly" || len(got.Chains) != 1 {
+		t.Errorf("unexpected report %+v", got)
+	}
+}
+
+func TestBuildDiscordReportSplitsEmbeds(t *testing.T) {
+	r := &DigestReport{Name: "Daily", Chains: make([]DigestChain, 12)}
+	messages := buildDiscordReport(r)
+	if len(messages) != 2 || len(messages[0].Embeds) != 10 || len(messages[1].Embeds) != 2 || messages[0].Content == "" {
+		t.Errorf("expected 10 and 2 embeds, got %d messages", len(messages))
+	}
+}
+
+func TestValidateDigests(t *testing.T) {
+	c := &Config{Chains: map[string]*ChainConfig{"osmosis": {}}}
+	if err := yaml.Unmarshal([]byte(`
+digests:
+  - name: Daily
+    schedule: "0 9 * * *"
+    timezone: Europe/Berlin
+    destinations: [slack]
+  - name: Broken
+    schedule: "0 9 * *"
+    destinations: [pagerduty]
+  - schedule: "@weekly"
+    timezone: Mars/Olympus
+    chains: [juno]
+`), c); err != nil {
+		t.Fatal(err)
+	}
+	fatal, problems := validateDigests(c)
+	if !fatal {
+		t.Error("invalid digests should be fatal")
+	}
+	expected := []string{
+		"warning: digest Daily is sent to slack, which is not enabled in default_alert_config",
+		`error: digest Broken: cron schedule "0 9 * *" must have 5 fields`,
+		"error: digest Broken cannot be sent to pagerduty",
+		"error: digest 3: unknown time zone Mars/Olympus",
+		"error: digest 3 has no destinations",
+		"warning: digest 3 has an unknown chain juno",
+	}
+	if strings.Join(problems, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected problems:\n%s", strings.Join(problems, "\n"))
+	}
+}
@@ -0,0 +1,176 @@
+package tenderduty
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// EscalationConfig notifies additional destinations in ordered stages while an alert stays unresolved. The stages
+// are driven from when the alert was first seen, and are in addition to the chain's regular destinations.
+type EscalationConfig struct {
+	Enabled *bool `yaml:"enabled"`
+	// AlertTypes limits escalation to alert IDs with one of these prefixes, all alerts are escalated if it is empty
+	AlertTypes []string `yaml:"alert_types"`
+	// SeverityThreshold is the minimum severity that is escalated, critical if it is not set
+	SeverityThreshold string            `yaml:"severity_threshold"`
+	Stages            []EscalationStage `yaml:"stages"`
+}
+
+// EscalationStage is a step in an escalation policy.
+type EscalationStage struct {
+	// After is how long the alert must have been active before this stage is notified
+	After time.Duration `yaml:"after"`
+	// Destinations are the names of the destinations to notify, for example pagerduty
+	Destinations []string `yaml:"destinations"`
+	// Alerts overrides the chain's destination settings for this stage, for example the api_key for a second
+	// PagerDuty service. Settings that are not set are taken from the chain.
+	Alerts *AlertConfig `yaml:"alerts"`
+}
+
+// applies reports whether an alert with the given ID and severity is escalated.
+func (e *EscalationConfig) applies(alertID, severity string) bool {
+	if !boolVal(e.Enabled) || len(e.Stages) == 0 {
+		return false
+	}
+	threshold := e.SeverityThreshold
+	if threshold == "" {
+		threshold = "critical"
+	}
+	if !slices.Contains(SeverityThresholdToSeverities(threshold), severity) {
+		return false
+	}
+	return len(e.AlertTypes) == 0 || hasAnyPrefix(alertID, e.AlertTypes)
+}
+
+// mergeStages fills in the stage overrides with the chain's alert settings. It is called once when the config is
+// loaded, the stages are copied since chains can share them through the default alert config.
+func (e *EscalationConfig) mergeStages(chainAlerts *AlertConfig) {
+	stages := make([]EscalationStage, len(e.Stages))
+	for i, stage := range e.Stages {
+		if stage.Alerts != nil {
+			merged := &AlertConfig{}
+			applyAlertDefaults(merged, stage.Alerts)
+			applyAlertDefaults(merged, chainAlerts)
+			stage.Alerts = merged
+		}
+		stages[i] = stage
+	}
+	e.Stages = stages
+}
+
+// stageNotifiers returns the destinations for a stage, using the stage's merged settings if it has overrides.
+func (e *EscalationConfig) stageNotifiers(stage int, chainAlerts *AlertConfig) []Notifier {
+	cfg := chainAlerts
+	if e.Stages[stage].Alerts != nil {
+		cfg = e.Stages[stage].Alerts
+	}
+	result := make([]Notifier, 0, len(e.Stages[stage].Destinations))
+	for _, name := range e.Stages[stage].Destinations {
+		if n, ok := getNotifier(name); ok {
+			result = append(result, escalationNotifier{Notifier: n, stage: stage + 1, cfg: cfg})
+		}
+	}
+	return result
+}
+
+// escalationNotifier delivers an escalation stage through one of the registered destinations. It has its own name
+// so that what was sent for each stage is tracked, and saved in the state file, separately from the regular alerts.
+type escalationNotifier struct {
+	Notifier
+	stage int
+	cfg   *AlertConfig
+}
+
+const escalationSeparator = "@escalation-"
+
+func (e escalationNotifier) Name() string {
+	return fmt.Sprintf("%s%s%d", e.Notifier.Name(), escalationSeparator, e.stage)
+}
+
+// Enabled is always true, listing a destination in a stage is what enables it.
+func (e escalationNotifier) Enabled(*AlertConfig) bool { return true }
+
+func (e escalationNotifier) SeverityThreshold(*AlertConfig) string {
+	return e.Notifier.SeverityThreshold(e.cfg)
+}
+
+func (e escalationNotifier) Send(msg *alertMsg) error {
+	staged := *msg
+	staged.alertConfig = e.cfg
+	return e.Notifier.Send(&staged)
+}
+
+// baseNotifierName strips the escalation stage from a destination name in the sent-state.
+func baseNotifierName(name string) string {
+	base, _, _ := strings.Cut(name, escalationSeparator)
+	return base
+}
+
+// escalatedNotifiers returns the stage destinations that were sent an alert, so they also get its resolution. The
+// caller must hold notifyMux.
+func (a *alarmCache) escalatedNotifiers(e *EscalationConfig, chainAlerts *AlertConfig, alertID string) []Notifier {
+	result := make([]Notifier, 0)
+	if !boolVal(e.Enabled) {
+		return result
+	}
+	for i := range e.Stages {
+		for _, n := range e.stageNotifiers(i, chainAlerts) {
+			if _, sent := a.Sent[n.Name()][alertID]; sent {
+				result = append(result, n)
+			}
+		}
+	}
+	return result
+}
+
+// escalate queues the active alerts that have reached an escalation stage. Each stage destination is only sent
+// the alert once, shouldNotify skips the ones that were already notified.
+func (c *Config) escalate() {
+	type active struct {
+		configName, id string
+		cache          alertMsgCache
+	}
+	pending := make([]active, 0)
+	alarms.notifyMux.RLock()
+	for configName, chainAlarms := range alarms.AllAlarms {
+		for id, cache := range chainAlarms {
+			// somebody is already looking at acknowledged alerts
+			if cache.AckedBy == "" {
+				pending = append(pending, active{configName: configName, id: id, cache: cache})
+			}
+		}
+	}
+	alarms.notifyMux.RUnlock()
+
+	for _, p := range pending {
+		c.chainsMux.RLock()
+		cc := c.Chains[p.configName]
+		if cc == nil || !cc.Alerts.Escalation.applies(p.id, p.cache.Severity) {
+			c.chainsMux.RUnlock()
+			continue
+		}
+		policy := &cc.Alerts.Escalation
+		notifiers := make([]Notifier, 0)
+		for i, stage := range policy.Stages {
+			if time.Since(p.cache.SentTime) >= stage.After {
+				notifiers = append(notifiers, policy.stageNotifiers(i, &cc.Alerts)...)
+			}
+		}
+		if len(notifiers) == 0 {
+			c.chainsMux.RUnlock()
+			continue
+		}
+		msg := c.newAlertMsg(p.configName, cc, p.cache.Message, p.cache.Severity, false, p.id)
+		msg.alertDetails = p.cache.details(p.id)
+		msg.eventID = p.cache.EventID
+		msg.notifiers = notifiers
+		c.chainsMux.RUnlock()
+		select {
+		case c.alertChan <- msg:
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package tenderduty
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-yaml/yaml"
+)
+
+func testEscalationConfig() EscalationConfig {
+	return EscalationConfig{
+		Enabled: boolPtr(true),
+		Stages: []EscalationStage{
+			{After: 0, Destinations: []string{"telegram"}},
+			{After: 10 * time.Minute, Destinations: []string{"pagerduty"}},
+			{After: 30 * time.Minute, Destinations: []string{"pagerduty"}, Alerts: &AlertConfig{Pagerduty: PDConfig{ApiKey: "second-service"}}},
+		},
+	}
+}
+
+func TestEscalationFromYAML(t *testing.T) {
+	ac := &AlertConfig{}
+	err := yaml.Unmarshal([]byte(`
+escalation:
+  enabled: yes
+  alert_types: [ConsecutiveBlocksMissed, ValidatorInactive]
+  stages:
+    - after: 0s
+      destinations: [telegram]
+    - after: 15m
+      destinations: [pagerduty]
+    - after: 45m
+      destinations: [pagerduty]
+      alerts:
+        pagerduty:
+          api_key: second-service
+`), ac)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	e := ac.Escalation
+	if !boolVal(e.Enabled) || len(e.AlertTypes) != 2 || len(e.Stages) != 3 {
+		t.Fatalf("unexpected escalation %+v", e)
+	}
+	if e.Stages[1].After != 15*time.Minute || e.Stages[2].Alerts == nil || e.Stages[2].Alerts.Pagerduty.ApiKey != "second-service" {
+		t.Errorf("unexpected stages %+v", e.Stages)
+	}
+}
+
+func TestEscalationApplies(t *testing.T) {
+	e := testEscalationConfig()
+	if !e.applies("ConsecutiveBlocksMissed_val1", "critical") {
+		t.Error("critical alerts should be escalated")
+	}
+	if e.applies("ConsecutiveBlocksMissed_val1", "warning") {
+		t.Error("warnings should not be escalated by default")
+	}
+	e.SeverityThreshold = "warning"
+	if !e.applies("ConsecutiveBlocksMissed_val1", "warning") {
+		t.Error("warnings should be escalated with a warning threshold")
+	}
+	e.AlertTypes = []string{"ValidatorInactive"}
+	if e.applies("ConsecutiveBlocksMissed_val1", "critical") {
+		t.Error("only the listed alert types should be escalated")
+	}
+	e.Enabled = boolPtr(false)
+	if e.applies("ValidatorInactive_val1", "critical") {
+		t.Error("disabled escalation should not apply")
+	}
+}
+
+func TestEscalationStageNotifiers(t *testing.T) {
+	e := testEscalationConfig()
+	chainAlerts := &AlertConfig{
+		Pagerduty:  PDConfig{ApiKey: "first-service", SeverityThreshold: "critical"},
+		Severities: map[string]string{"StakeChange": "info"},
+	}
+	shared := e.Stages
+	shared[2].Alerts.Severities = map[string]string{"ValidatorInactive": "critical"}
+	e.mergeStages(chainAlerts)
+	if shared[2].Alerts.Pagerduty.SeverityThreshold != "" || len(shared[2].Alerts.Severities) != 1 {
+		t.Error("merging should not change stages shared with other chains")
+	}
+	if len(e.Stages[2].Alerts.Severities) != 2 {
+		t.Errorf("stage severities should be merged with the chain's, got %v", e.Stages[2].Alerts.Severities)
+	}
+
+	first := e.stageNotifiers(1, chainAlerts)
+	if len(first) != 1 || first[0].Name() != "pagerduty@escalation-2" {
+		t.Fatalf("unexpected notifiers %v", first)
+	}
+	if first[0].(escalationNotifier).cfg.Pagerduty.ApiKey != "first-service" {
+		t.Error("stage without overrides should use the chain settings")
+	}
+
+	second := e.stageNotifiers(2, chainAlerts)
+	cfg := second[0].(escalationNotifier).cfg
+	if cfg.Pagerduty.ApiKey != "second-service" || cfg.Pagerduty.SeverityThreshold != "critical" {
+		t.Errorf("stage overrides should be merged with the chain settings, got %+v", cfg.Pagerduty)
+	}
+	if !second[0].Enabled(&AlertConfig{}) {
+		t.Error("stage destinations are enabled by being listed")
+	}
+	if baseNotifierName(second[0].Name()) != "pagerduty" {
+		t.Errorf("unexpected base name %s", baseNotifierName(second[0].Name()))
+	}
+}
+
+func TestEscalate(t *testing.T) {
+	originalTd := td
+	td = createTestConfig()
+	td.ctx, td.cancel = context.WithCancel(context.Background())
+	defer td.cancel()
+	td.Chains["test-chain"].Alerts.Escalation = testEscalationConfig()
+	newTestAlarmCache(t).AllAlarms = map[string]map[string]alertMsgCache{
+		"test-chain": {
+			"ConsecutiveBlocksMissed_testval123": {Message: "missed", Severity: "critical", SentTime: time.Now().Add(-20 * time.Minute)},
+			"StakeChange_testval123":             {Message: "stake", Severity: "warning", SentTime: time.Now().Add(-time.Hour)},
+		},
+	}
+	defer func() { td = originalTd }()
+
+	td.escalate()
+
+	if len(td.alertChan) != 1 {
+		t.Fatalf("expected 1 escalation to be queued, got %d", len(td.alertChan))
+	}
+	msg := <-td.alertChan
+	if msg.uniqueId != "ConsecutiveBlocksMissed_testval123" || msg.resolved {
+		t.Fatalf("unexpected escalation %+v", msg)
+	}
+	names := make([]string, 0)
+	for _, n := range msg.notifiers {
+		names = append(names, n.Name())
+	}
+	if len(names) != 2 || names[0] != "telegram@escalation-1" || names[1] != "pagerduty@escalation-2" {
+		t.Errorf("expected the first two stages, got %v", names)
+	}
+
+	// each stage is only notified once
+	if !notifyAndRecord(msg, msg.notifiers[1]) {
+		t.Error("first escalation to pagerduty should notify")
+	}
+	if notifyAndRecord(msg, msg.notifiers[1]) {
+		t.Error("escalation should not be repeated")
+	}
+
+	// the resolution goes to the stages that were notified, and the alert is no longer escalated
+	id := "ConsecutiveBlocksMissed_testval123"
+	td.alert("test-chain", "missed", "critical", true, &id, alertDetails{})
+	resolved := <-td.alertChan
+	if !resolved.notifies("pagerduty@escalation-2") || resolved.notifies("telegram@escalation-1") {
+		t.Errorf("resolution should go to the notified stage only, got %v", resolved.notifiers)
+	}
+	if !shouldNotify(resolved, resolved.notifiers[len(resolved.notifiers)-1]) {
+		t.Error("resolution should notify the escalated destination")
+	}
+	td.escalate()
+	if len(td.alertChan) != 0 {
+		t.Error("resolved alerts should not be escalated")
+	}
+}
+
+func TestAlertKeepsFirstSeen(t *testing.T) {
+	originalTd := td
+	td = createTestConfig()
+	firstSeen := time.Now().Add(-time.Hour).Truncate(time.Second)
+	newTestAlarmCache(t).AllAlarms = map[string]map[string]alertMsgCache{
+		"test-chain": {"ValidatorInactive_testval123": {Message: "jailed", Severity: "critical", SentTime: firstSeen}},
+	}
+	defer func() { td = originalTd }()
+
+	id := "ValidatorInactive_testval123"
+	td.alert("test-chain", "jailed", "critical", false, &id, alertDetails{})
+	<-td.alertChan
+	if got := alarms.AllAlarms["test-chain"][id].SentTime; !got.Equal(firstSeen) {
+		t.Errorf("first seen time should be kept, got %s want %s", got, firstSeen)
+	}
+}
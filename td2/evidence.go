@@ -0,0 +1,167 @@
+package tenderduty
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// evidenceAlertTTL is how long double sign and evidence alerts stay active, they are about a single event and have
+// no condition that clears them. They are cleared without sending a resolution, nothing was fixed.
+const evidenceAlertTTL = 24 * time.Hour
+
+// rawEvidence is a trimmed down version of the evidence in a block, it is either a DuplicateVoteEvidence or a
+// LightClientAttackEvidence.
+type rawEvidence struct {
+	Type  string `json:"type"`
+	Value struct {
+		VoteA               *rawEvidenceVote `json:"vote_a"`
+		VoteB               *rawEvidenceVote `json:"vote_b"`
+		CommonHeight        stringInt64      `json:"common_height"`
+		ByzantineValidators []struct {
+			Address string `json:"address"`
+		} `json:"byzantine_validators"`
+	} `json:"value"`
+}
+
+type rawEvidenceVote struct {
+	Height           stringInt64 `json:"height"`
+	Round            int32       `json:"round"`
+	ValidatorAddress string      `json:"validator_address"`
+}
+
+// misbehaviour is the evidence against validators in a block, or two conflicting votes seen from our validator.
+type misbehaviour struct {
+	// kind is the evidence type such as DuplicateVoteEvidence, or ConflictingVotes
+	kind string
+	// height is when the validators misbehaved, and block is the block that included the evidence
+	height     int64
+	block      int64
+	validators []string
+}
+
+// evidence returns the misbehaviour in a block's evidence.
+func (rb rawBlock) evidence() []misbehaviour {
+	found := make([]misbehaviour, 0)
+	block := rb.Block.Header.Height.val()
+	for _, ev := range rb.Block.Evidence.Evidence {
+		m := misbehaviour{kind: strings.TrimPrefix(ev.Type, "tendermint/"), block: block}
+		switch {
+		case ev.Value.VoteA != nil:
+			m.height = ev.Value.VoteA.Height.val()
+			m.validators = append(m.validators, ev.Value.VoteA.ValidatorAddress)
+		case len(ev.Value.ByzantineValidators) > 0:
+			m.height = ev.Value.CommonHeight.val()
+			for _, v := range ev.Value.ByzantineValidators {
+				m.validators = append(m.validators, v.Address)
+			}
+		default:
+			continue
+		}
+		found = append(found, m)
+	}
+	return found
+}
+
+// signedVote is the block our validator voted for at a height, round and vote type.
+type signedVote struct {
+	height int64
+	block  string
+}
+
+// voteConflicts remembers the votes of our validator to find two different votes for the same height, round and
+// vote type, which is what a double sign looks like before the evidence is included in a block.
+type voteConflicts map[string]signedVote
+
+// conflicts records a vote and reports if the validator already voted for a different block.
+func (vc voteConflicts) conflicts(vote *rawVote) bool {
+	height := vote.Vote.Height.val()
+	key := fmt.Sprintf("%d/%d/%d", height, vote.Vote.Round, vote.Vote.Type)
+	prev, seen := vc[key]
+	if !seen {
+		vc[key] = signedVote{height: height, block: vote.Vote.BlockID.Hash}
+		for k, v := range vc {
+			if v.height <= height-trackedHeights {
+				delete(vc, k)
+			}
+		}
+		return false
+	}
+	return prev.block != vote.Vote.BlockID.Hash
+}
+
+// alertMisbehaviour raises a critical alert when our validator misbehaved, and an info alert for other validators
+// when evidence_alerts is on.
+func (cc *ChainConfig) alertMisbehaviour(found []misbehaviour, address string) {
+	for _, m := range found {
+		for _, val := range m.validators {
+			details := alertDetails{labels: map[string]string{
+				"evidence":  m.kind,
+				"height":    strconv.FormatInt(m.height, 10),
+				"validator": val,
+			}}
+			var alertID, message string
+			switch {
+			case val == address && !cc.doubleSignAlerts():
+				continue
+			case val == address && m.kind == "ConflictingVotes":
+				details.kind = kindDoubleSign
+				alertID = fmt.Sprintf("DoubleSign_%s_%d", cc.ValAddress, m.height)
+				message = fmt.Sprintf("%s signed two conflicting votes at height %d on %s, check for a second signer now", cc.valInfo.Moniker, m.height, cc.ChainId)
+			case val == address:
+				details.kind = kindDoubleSign
+				alertID = fmt.Sprintf("DoubleSign_%s_%d", cc.ValAddress, m.height)
+				message = fmt.Sprintf("%s double signed at height %d on %s, %s was included in block %d", cc.valInfo.Moniker, m.height, cc.ChainId, m.kind, m.block)
+			case !boolVal(cc.Alerts.EvidenceAlerts):
+				continue
+			default:
+				details.kind = kindEvidence
+				alertID = fmt.Sprintf("Evidence_%s_%d", val, m.height)
+				message = fmt.Sprintf("validator %s misbehaved at height %d on %s, %s was included in block %d", val, m.height, cc.ChainId, m.kind, m.block)
+			}
+			l(slog.LevelWarn, fmt.Sprintf("☠️ %-12s %s", cc.ChainId, message))
+			if alarms.exist(cc.name, alertID) {
+				continue
+			}
+			severity := "critical"
+			if details.kind == kindEvidence {
+				severity = "info"
+			}
+			td.alert(cc.name, message, severity, false, &alertID, details)
+		}
+	}
+	cc.activeAlerts = alarms.getCount(cc.name)
+}
+
+// doubleSignAlerts is on unless double_sign_alerts is set to no.
+func (cc *ChainConfig) doubleSignAlerts() bool {
+	return cc.Alerts.DoubleSignAlerts == nil || *cc.Alerts.DoubleSignAlerts
+}
+
+// evaluateEvidenceAlert clears the double sign and evidence alerts that have been active for evidenceAlertTTL.
+func evaluateEvidenceAlert(cc *ChainConfig) (bool, bool) {
+	resolved := false
+
+	expired := make([]string, 0)
+	alarms.notifyMux.RLock()
+	for alertID, cache := range alarms.AllAlarms[cc.name] {
+		kind := cache.details(alertID).kind
+		if (kind == kindDoubleSign || kind == kindEvidence) && time.Since(cache.SentTime) > evidenceAlertTTL {
+			expired = append(expired, alertID)
+		}
+	}
+	alarms.notifyMux.RUnlock()
+
+	for _, alertID := range expired {
+		l(slog.LevelInfo, fmt.Sprintf("🧹 %-12s clearing %s after %s", cc.ChainId, alertID, evidenceAlertTTL))
+		for _, msg := range alarms.expire(cc.name, alertID) {
+			td.alertChan <- msg
+		}
+		resolved = true
+	}
+
+	cc.activeAlerts = alarms.getCount(cc.name)
+	return false, resolved
+}
@@ -0,0 +1,119 @@
+package tenderduty
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestBlockEvidence(t *testing.T) {
+	b := &rawBlock{}
+	err := json.Unmarshal([]byte(`{"block":{"header":{"height":"105"},"evidence":{"evidence":[
+		{"type":"tendermint/DuplicateVoteEvidence","value":{"vote_a":{"height":"100","round":0,"validator_address":"AAAA"},"vote_b":{"height":"100","round":0,"validator_address":"AAAA"}}},
+		{"type":"tendermint/LightClientAttackEvidence","value":{"common_height":"98","byzantine_validators":[{"address":"BBBB"},{"address":"CCCC"}]}}
+	]}}}`), b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := b.evidence()
+	if len(found) != 2 {
+		t.Fatalf("expected 2 pieces of evidence, got %+v", found)
+	}
+	if found[0].kind != "DuplicateVoteEvidence" || found[0].height != 100 || found[0].block != 105 || len(found[0].validators) != 1 || found[0].validators[0] != "AAAA" {
+		t.Errorf("unexpected duplicate vote evidence %+v", found[0])
+	}
+	if found[1].kind != "LightClientAttackEvidence" || found[1].height != 98 || len(found[1].validators) != 2 || found[1].validators[1] != "CCCC" {
+		t.Errorf("unexpected light client attack evidence %+v", found[1])
+	}
+}
+
+func TestVoteConflicts(t *testing.T) {
+	vote := func(height int64, round int32, hash string) *rawVote {
+		v := &rawVote{}
+		v.Vote.Type = 2
+		v.Vote.Height = stringInt64(strconv.FormatInt(height, 10))
+		v.Vote.Round = round
+		v.Vote.BlockID.Hash = hash
+		return v
+	}
+	vc := make(voteConflicts)
+	if vc.conflicts(vote(10, 0, "AA")) || vc.conflicts(vote(10, 0, "AA")) {
+		t.Error("the same vote from two nodes is not a conflict")
+	}
+	if vc.conflicts(vote(10, 1, "BB")) {
+		t.Error("a vote in another round is not a conflict")
+	}
+	if !vc.conflicts(vote(10, 0, "BB")) {
+		t.Error("two votes for different blocks should conflict")
+	}
+	vc.conflicts(vote(10+trackedHeights, 0, "AA"))
+	if _, ok := vc["10/0/2"]; ok {
+		t.Error("old votes should be forgotten")
+	}
+}
+
+func TestAlertMisbehaviour(t *testing.T) {
+	setupOutboxTest(t)
+	cc := td.Chains["test-chain"]
+	cc.valInfo = &ValInfo{Moniker: "testval"}
+	found := []misbehaviour{
+		{kind: "DuplicateVoteEvidence", height: 100, block: 105, validators: []string{"AAAA"}},
+		{kind: "LightClientAttackEvidence", height: 98, block: 105, validators: []string{"BBBB"}},
+	}
+
+	cc.alertMisbehaviour(found, "AAAA")
+	msgs := drainAlerts()
+	if len(msgs) != 1 || msgs[0].severity != "critical" || msgs[0].uniqueId != "DoubleSign_testval123_100" {
+		t.Fatalf("expected a critical double sign alert only, got %+v", msgs)
+	}
+	// the same evidence from another node is not raised again
+	cc.alertMisbehaviour(found, "AAAA")
+	if msgs = drainAlerts(); len(msgs) != 0 {
+		t.Fatalf("expected no new alerts, got %+v", msgs)
+	}
+
+	on := true
+	cc.Alerts.EvidenceAlerts = &on
+	cc.alertMisbehaviour(found, "AAAA")
+	if msgs = drainAlerts(); len(msgs) != 1 || msgs[0].severity != "info" || msgs[0].uniqueId != "Evidence_BBBB_98" {
+		t.Fatalf("expected an info alert for the other validator, got %+v", msgs)
+	}
+
+	off := false
+	cc.Alerts.DoubleSignAlerts = &off
+	cc.alertMisbehaviour([]misbehaviour{{kind: "ConflictingVotes", height: 110, block: 110, validators: []string{"AAAA"}}}, "AAAA")
+	if msgs = drainAlerts(); len(msgs) != 0 {
+		t.Fatalf("double sign alerts are off, got %+v", msgs)
+	}
+}
+
+func TestEvaluateEvidenceAlert(t *testing.T) {
+	setupOutboxTest(t)
+	cc := td.Chains["test-chain"]
+	cc.valInfo = &ValInfo{Moniker: "testval"}
+	cc.alertMisbehaviour([]misbehaviour{{kind: "ConflictingVotes", height: 110, block: 110, validators: []string{"AAAA"}}}, "AAAA")
+	drainAlerts()
+	id := "DoubleSign_testval123_110"
+
+	if _, resolved := evaluateEvidenceAlert(cc); resolved || !alarms.exist("test-chain", id) {
+		t.Fatal("a new double sign alert should stay active")
+	}
+	alarms.notifyMux.Lock()
+	cache := alarms.AllAlarms["test-chain"][id]
+	cache.SentTime = time.Now().Add(-evidenceAlertTTL - time.Minute)
+	alarms.AllAlarms["test-chain"][id] = cache
+	alarms.sentFor("pagerduty")[id] = alertMsgCache{Message: cache.Message, SentTime: cache.SentTime}
+	alarms.notifyMux.Unlock()
+
+	if _, resolved := evaluateEvidenceAlert(cc); !resolved || alarms.exist("test-chain", id) {
+		t.Fatal("the double sign alert should be cleared after the TTL")
+	}
+	// nothing was fixed, so no resolution is sent
+	if msgs := drainAlerts(); len(msgs) != 0 {
+		t.Fatalf("expected no resolution, got %+v", msgs)
+	}
+	if _, ok := alarms.sentFor("pagerduty")[id]; ok {
+		t.Error("the sent state should be cleared with the alert")
+	}
+}
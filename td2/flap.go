@@ -0,0 +1,157 @@
+package tenderduty
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// FlapConfig controls flap detection. An alert that fires and resolves too often within the window is flapping: a
+// single flapping notice is sent, and its final state once it has been stable for the length of the window.
+type FlapConfig struct {
+	// Enabled is on unless it is set to no
+	Enabled *bool `yaml:"enabled"`
+	// Window is the sliding window the state changes are counted in, 10m by default
+	Window time.Duration `yaml:"window"`
+	// Transitions is how many times an alert must fire or resolve within the window to be flapping, 4 by default
+	Transitions int `yaml:"transitions"`
+}
+
+// flapSettings returns the flap detection settings with the defaults filled in.
+func flapSettings() FlapConfig {
+	f := FlapConfig{}
+	if td != nil {
+		f = td.Flapping
+	}
+	if f.Window <= 0 {
+		f.Window = 10 * time.Minute
+	}
+	if f.Transitions <= 0 {
+		f.Transitions = 4
+	}
+	return f
+}
+
+func (f FlapConfig) enabled() bool {
+	return f.Enabled == nil || *f.Enabled
+}
+
+// flapPhase marks the messages sent by the flap detection, they go out even though the alert was already sent.
+type flapPhase int
+
+const (
+	flapNone flapPhase = iota
+	// flapNotice tells that the alert is flapping
+	flapNotice
+	// flapSettled is the final state of an alert that stopped flapping
+	flapSettled
+)
+
+// flapState tracks the recent state changes of an alert.
+type flapState struct {
+	changes  []time.Time
+	flapping bool
+	// latest is the state of the alert while it is flapping, it is sent once the alert settles
+	latest *alertMsg
+}
+
+// flapped records that an alert fired or resolved. It returns the message to send in its place, which is the alert
+// itself, a flapping notice when it just started flapping, or nil when it is flapping and held back. The caller must
+// hold notifyMux.
+func (a *alarmCache) flapped(msg *alertMsg) *alertMsg {
+	settings := flapSettings()
+	if !settings.enabled() {
+		return msg
+	}
+	if a.flaps == nil {
+		a.flaps = make(map[string]map[string]*flapState)
+	}
+	if a.flaps[msg.configName] == nil {
+		a.flaps[msg.configName] = make(map[string]*flapState)
+	}
+	state := a.flaps[msg.configName][msg.uniqueId]
+	if state == nil {
+		state = &flapState{}
+		a.flaps[msg.configName][msg.uniqueId] = state
+	}
+	now := time.Now()
+	state.changes = append(recentChanges(state.changes, now.Add(-settings.Window)), now)
+	if state.flapping {
+		state.latest = msg
+		l(slog.LevelInfo, fmt.Sprintf("🔁 Flapping     alarm on %s (%s) - holding notifications", msg.chain, msg.message))
+		return nil
+	}
+	if len(state.changes) < settings.Transitions {
+		return msg
+	}
+	state.flapping = true
+	state.latest = msg
+	notice := *msg
+	notice.resolved = false
+	notice.flap = flapNotice
+	notice.message = fmt.Sprintf("%s is flapping, it changed state %d times in %s. Notifications are held until it is stable for %s.",
+		msg.message, len(state.changes), settings.Window, settings.Window)
+	l(slog.LevelWarn, fmt.Sprintf("🔁 flapping detected on %s (%s)", msg.chain, msg.message))
+	return &notice
+}
+
+func recentChanges(changes []time.Time, since time.Time) []time.Time {
+	for len(changes) > 0 && changes[0].Before(since) {
+		changes = changes[1:]
+	}
+	return changes
+}
+
+// settleFlapping returns the final state of the alerts that stopped flapping, and forgets alerts that have been quiet
+// for a while.
+func (a *alarmCache) settleFlapping() []*alertMsg {
+	settings := flapSettings()
+	since := time.Now().Add(-settings.Window)
+	settled := make([]*alertMsg, 0)
+	a.notifyMux.Lock()
+	defer a.notifyMux.Unlock()
+	for chain, states := range a.flaps {
+		for id, state := range states {
+			if len(state.changes) > 0 && state.changes[len(state.changes)-1].After(since) {
+				continue
+			}
+			delete(states, id)
+			if !state.flapping || state.latest == nil {
+				continue
+			}
+			msg := *state.latest
+			msg.flap = flapSettled
+			if !msg.resolved {
+				msg.message += " (stopped flapping)"
+			}
+			settled = append(settled, &msg)
+		}
+		if len(states) == 0 {
+			delete(a.flaps, chain)
+		}
+	}
+	return settled
+}
+
+// settleFlapping sends the final state of the alerts that stopped flapping.
+func (c *Config) settleFlapping() {
+	for _, msg := range alarms.settleFlapping() {
+		select {
+		case c.alertChan <- msg:
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+// validateFlapping checks the flap detection settings in the config file.
+func validateFlapping(f *FlapConfig) (fatal bool, problems []string) {
+	if f.Window < 0 || f.Transitions < 0 {
+		fatal = true
+		problems = append(problems, "error: flapping window and transitions cannot be negative")
+	}
+	if f.Transitions == 1 {
+		problems = append(problems, "warning: flapping transitions of 1 treats every alert as flapping")
+	}
+	return
+}
@@ -0,0 +1,110 @@
+package tenderduty
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// drainAlerts returns the alerts queued by td.alert.
+func drainAlerts() []*alertMsg {
+	msgs := make([]*alertMsg, 0)
+	for {
+		select {
+		case msg := <-td.alertChan:
+			msgs = append(msgs, msg)
+		default:
+			return msgs
+		}
+	}
+}
+
+func TestFlapDetection(t *testing.T) {
+	setupOutboxTest(t)
+	td.Flapping = FlapConfig{Window: time.Minute, Transitions: 4}
+	id := "RPCNodeDown_https://rpc.example.com"
+
+	for _, resolved := range []bool{false, true, false} {
+		td.alert("test-chain", "node down", "critical", resolved, &id, alertDetails{})
+	}
+	if msgs := drainAlerts(); len(msgs) != 3 {
+		t.Fatalf("expected 3 alerts before flapping, got %d", len(msgs))
+	}
+	// raising an active alert again is not a state change
+	td.alert("test-chain", "node down", "critical", false, &id, alertDetails{})
+	if msgs := drainAlerts(); len(msgs) != 1 || msgs[0].flap != flapNone {
+		t.Fatal("raising an alert again should not count")
+	}
+
+	td.alert("test-chain", "node down", "critical", true, &id, alertDetails{})
+	msgs := drainAlerts()
+	if len(msgs) != 1 || msgs[0].flap != flapNotice || msgs[0].resolved || !strings.Contains(msgs[0].message, "is flapping, it changed state 4 times in 1m0s") {
+		t.Fatalf("expected a flapping notice, got %+v", msgs)
+	}
+
+	// while flapping the changes are held back
+	td.alert("test-chain", "node down", "critical", false, &id, alertDetails{})
+	td.alert("test-chain", "node down", "critical", true, &id, alertDetails{})
+	if msgs := drainAlerts(); len(msgs) != 0 {
+		t.Fatalf("flapping alert should be held, got %d messages", len(msgs))
+	}
+	if alarms.exist("test-chain", id) {
+		t.Error("the alert state should still be tracked while flapping")
+	}
+	if settled := alarms.settleFlapping(); len(settled) != 0 {
+		t.Fatal("the alert has not been stable for the window yet")
+	}
+
+	// stable for the window, the final state is sent
+	alarms.notifyMux.Lock()
+	state := alarms.flaps["test-chain"][id]
+	for i := range state.changes {
+		state.changes[i] = state.changes[i].Add(-2 * time.Minute)
+	}
+	alarms.notifyMux.Unlock()
+	settled := alarms.settleFlapping()
+	if len(settled) != 1 || !settled[0].resolved || settled[0].flap != flapSettled {
+		t.Fatalf("expected the resolution once settled, got %+v", settled)
+	}
+	if len(alarms.flaps) != 0 {
+		t.Error("settled alerts should be forgotten")
+	}
+}
+
+func TestFlapDetectionDisabled(t *testing.T) {
+	setupOutboxTest(t)
+	off := false
+	td.Flapping = FlapConfig{Enabled: &off, Transitions: 2}
+	id := "RPCNodeDown_https://rpc.example.com"
+	for _, resolved := range []bool{false, true, false, true} {
+		td.alert("test-chain", "node down", "critical", resolved, &id, alertDetails{})
+	}
+	for _, msg := range drainAlerts() {
+		if msg.flap != flapNone {
+			t.Fatal("flap detection should be off")
+		}
+	}
+}
+
+func TestShouldNotifyFlapMessages(t *testing.T) {
+	setupOutboxTest(t)
+	dest := discordNotifier{}
+	msg := testOutboxMsg(false)
+	if !notifyAndRecord(msg, dest) {
+		t.Fatal("the first alert should be sent")
+	}
+	if shouldNotify(msg, dest) {
+		t.Error("the alert was already sent")
+	}
+
+	notice := *msg
+	notice.flap = flapNotice
+	if !shouldNotify(&notice, dest) {
+		t.Error("the flapping notice should be sent even though the alert was sent")
+	}
+	settled := *msg
+	settled.flap = flapSettled
+	if !shouldNotify(&settled, dest) {
+		t.Error("the final state should be sent")
+	}
+}
@@ -0,0 +1,245 @@
+package tenderduty
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GroupingConfig merges alerts that arrive close together into one message per destination, for example when a
+// shared RPC provider goes down and every chain raises RPCNodeDown at once.
+type GroupingConfig struct {
+	// Window is how long alerts are collected before they are sent, grouping is off when it is not set
+	Window time.Duration `yaml:"window"`
+	// By are the parts of the group key: kind, chain, severity, node_host, or label:<name> for an alert label.
+	// Alerts are grouped by kind when it is not set.
+	By []string `yaml:"by"`
+	// AlertTypes limits grouping to some alert types, all types by default
+	AlertTypes []string `yaml:"alert_types"`
+	// Destinations limits grouping to some destinations, all destinations by default
+	Destinations []string `yaml:"destinations"`
+}
+
+var groupKeyFields = []string{"kind", "chain", "severity", "node_host"}
+
+// resolvedByID are the destinations that match a resolution to its alert by the alert's ID, the webhook's receivers
+// do so by its fingerprint. A grouped message has an ID of its own that would not resolve the alerts in it, so these
+// always get one message per alert.
+var resolvedByID = []string{"pagerduty", "opsgenie", "alertmanager", "webhook"}
+
+// applies tells if an alert to a destination is grouped.
+func (g *GroupingConfig) applies(msg *alertMsg, destination string) bool {
+	if g.Window <= 0 || slices.Contains(resolvedByID, baseNotifierName(destination)) {
+		return false
+	}
+	if len(g.AlertTypes) > 0 && !slices.Contains(g.AlertTypes, string(msg.kind)) {
+		return false
+	}
+	return len(g.Destinations) == 0 || slices.Contains(g.Destinations, baseNotifierName(destination))
+}
+
+// key is the group an alert belongs to.
+func (g *GroupingConfig) key(msg *alertMsg) string {
+	by := g.By
+	if len(by) == 0 {
+		by = []string{"kind"}
+	}
+	parts := make([]string, 0, len(by))
+	for _, field := range by {
+		var v string
+		switch field {
+		case "kind":
+			v = string(msg.kind)
+		case "chain":
+			v = msg.configName
+		case "severity":
+			v = msg.severity
+		case "node_host":
+			if u, err := url.Parse(msg.labels["node"]); err == nil {
+				v = u.Hostname()
+			}
+		default:
+			v = msg.labels[strings.TrimPrefix(field, "label:")]
+		}
+		parts = append(parts, field+"="+v)
+	}
+	return strings.Join(parts, ",")
+}
+
+// alertGroups holds the groups whose window is open, keyed by destination and group key.
+type alertGroups struct {
+	mux    sync.Mutex
+	open   map[string][]*outboxEntry
+	timers map[string]*time.Timer
+	// flushing counts the groups that wait for their window to close or are being sent
+	flushing sync.WaitGroup
+}
+
+func newAlertGroups() *alertGroups {
+	return &alertGroups{open: make(map[string][]*outboxEntry), timers: make(map[string]*time.Timer)}
+}
+
+var groups = newAlertGroups()
+
+// add puts an outbox entry in its group, it returns false when the entry is not grouped and should be sent now.
+func (g *alertGroups) add(e *outboxEntry) bool {
+	settings := td.Grouping
+	if !settings.applies(e.msg, e.Destination) {
+		return false
+	}
+	// alerts and resolutions are never in the same group, and the chains must share the destination's settings, for
+	// example the same Slack webhook
+	key := e.Destination + "/" + settings.key(e.msg) + "/" + destinationSettings(e.msg.alertConfig, e.Destination)
+	if e.Resolved {
+		key += "/resolved"
+	}
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	if _, ok := g.open[key]; !ok {
+		g.flushing.Add(1)
+		g.timers[key] = time.AfterFunc(settings.Window, func() {
+			defer g.flushing.Done()
+			g.flush(key)
+		})
+	}
+	g.open[key] = append(g.open[key], e)
+	return true
+}
+
+// flush sends a group once its window closed.
+func (g *alertGroups) flush(key string) {
+	g.mux.Lock()
+	entries := g.open[key]
+	delete(g.open, key)
+	delete(g.timers, key)
+	g.mux.Unlock()
+	switch len(entries) {
+	case 0:
+	case 1:
+		_ = outbox.attempt(entries[0])
+	default:
+		_ = outbox.attemptGroup(entries)
+	}
+}
+
+// stop drops the open groups without sending them, and waits for the groups that are already being sent.
+func (g *alertGroups) stop() {
+	g.mux.Lock()
+	for key, t := range g.timers {
+		if t.Stop() {
+			g.flushing.Done()
+		}
+		delete(g.timers, key)
+		delete(g.open, key)
+	}
+	g.mux.Unlock()
+	g.flushing.Wait()
+}
+
+// attemptGroup delivers the entries of a group as one message. Each entry gets the result, so entries that fail are
+// retried on their own.
+func (o *outboxStore) attemptGroup(entries []*outboxEntry) error {
+	first := entries[0]
+	msg := groupedMsg(entries)
+	l(slog.LevelInfo, fmt.Sprintf("📦 sending %d grouped notifications to %s", len(entries), first.Destination))
+	err := first.dest.Send(msg)
+	for _, e := range entries {
+		_ = o.finish(e, err)
+	}
+	return err
+}
+
+// groupedMsg merges the alerts of a group into one message that lists the affected chains.
+func groupedMsg(entries []*outboxEntry) *alertMsg {
+	first := entries[0].msg
+	msg := *first
+	chains := make([]string, 0)
+	lines := make([]string, 0, len(entries))
+	severities := []string{"info", "warning", "critical"}
+	for _, e := range entries {
+		if !slices.Contains(chains, e.msg.configName) {
+			chains = append(chains, e.msg.configName)
+		}
+		if slices.Index(severities, e.msg.severity) > slices.Index(severities, msg.severity) {
+			msg.severity = e.msg.severity
+		}
+		if e.msg.kind != msg.kind {
+			msg.kind = ""
+		}
+		lines = append(lines, fmt.Sprintf("• %s: %s", e.msg.configName, e.msg.templated(e.Destination).message))
+	}
+	what := string(msg.kind)
+	if what == "" {
+		what = "alerts"
+	}
+	state := "firing"
+	if msg.resolved {
+		state = "resolved"
+	}
+	msg.chain = strings.Join(chains, ", ")
+	msg.message = fmt.Sprintf("%d %s %s on %s:\n%s", len(entries), what, state, msg.chain, strings.Join(lines, "\n"))
+	// the same ID for the alerts and their resolution, so destinations that deduplicate can resolve the group
+	msg.uniqueId = "Group_" + td.Grouping.key(first)
+	msg.labels = nil
+	msg.value, msg.threshold = 0, 0
+	msg.chainId, msg.chainName, msg.moniker, msg.valoperAddress, msg.valconsAddress = "", "", "", "", ""
+	if first.alertConfig != nil {
+		// the buttons act on a single alert
+		cfg := *first.alertConfig
+		off := false
+		cfg.Slack.Interactive, cfg.Discord.Interactive = &off, &off
+		msg.alertConfig = &cfg
+	}
+	return &msg
+}
+
+// destinationSettings returns a destination's settings from an alert config as JSON. The fields of AlertConfig have
+// the same yaml names as the destinations.
+func destinationSettings(cfg *AlertConfig, destination string) string {
+	if cfg == nil {
+		return ""
+	}
+	name := baseNotifierName(destination)
+	v := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).Tag.Get("yaml") == name {
+			b, _ := json.Marshal(v.Field(i).Interface())
+			return string(b)
+		}
+	}
+	return ""
+}
+
+// validateGrouping checks the grouping settings in the config file.
+func validateGrouping(g *GroupingConfig) (fatal bool, problems []string) {
+	if g.Window < 0 {
+		fatal = true
+		problems = append(problems, "error: grouping window cannot be negative")
+	}
+	for _, field := range g.By {
+		if !slices.Contains(groupKeyFields, field) && (!strings.HasPrefix(field, "label:") || field == "label:") {
+			fatal = true
+			problems = append(problems, fmt.Sprintf("error: unknown grouping field %s, valid choices are %s and label:<name>", field, strings.Join(groupKeyFields, ", ")))
+		}
+	}
+	for _, kind := range g.AlertTypes {
+		if !slices.Contains(alertKinds, alertKind(kind)) {
+			problems = append(problems, fmt.Sprintf("warning: grouping has an unknown alert type %s", kind))
+		}
+	}
+	for _, dest := range g.Destinations {
+		if _, ok := getNotifier(dest); !ok {
+			fatal = true
+			problems = append(problems, fmt.Sprintf("error: grouping has an unknown destination %s", dest))
+		} else if slices.Contains(resolvedByID, dest) {
+			problems = append(problems, fmt.Sprintf("warning: %s resolves alerts by their ID, it is sent one message per alert and not grouped", dest))
+		}
+	}
+	return
+}
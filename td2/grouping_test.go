@@ -0,0 +1,146 @@
+package tenderduty
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func testNodeDownMsg(chain, node string, resolved bool) *alertMsg {
+	return &alertMsg{
+		configName:   chain,
+		chain:        chain,
+		uniqueId:     "RPCNodeDown_" + node,
+		message:      "RPC node " + node + " has been down",
+		severity:     "critical",
+		resolved:     resolved,
+		alertDetails: alertDetails{kind: kindRPCNodeDown, labels: map[string]string{"node": node}},
+		alertConfig:  &AlertConfig{},
+	}
+}
+
+func setupGroupingTest(t *testing.T, settings GroupingConfig) {
+	setupOutboxTest(t)
+	original := groups
+	groups = newAlertGroups()
+	td.Grouping = settings
+	t.Cleanup(func() {
+		// the timers read the config, they must be done before it is restored
+		groups.stop()
+		groups = original
+	})
+}
+
+func TestGroupingMergesAlerts(t *testing.T) {
+	setupGroupingTest(t, GroupingConfig{Window: 200 * time.Millisecond, By: []string{"kind", "node_host"}})
+	dest := newFlakyNotifier(false)
+
+	for _, chain := range []string{"osmosis", "juno", "stargaze"} {
+		if err := deliver(testNodeDownMsg(chain, "https://rpc.provider.com:443/"+chain, false), dest); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// another host is a different group
+	_ = deliver(testNodeDownMsg("cosmoshub", "https://rpc.other.net", false), dest)
+	dest.mux.Lock()
+	early := len(*dest.sent)
+	dest.mux.Unlock()
+	if early != 0 {
+		t.Fatal("alerts should wait for the grouping window")
+	}
+
+	groups.flushing.Wait()
+	dest.mux.Lock()
+	sent := *dest.sent
+	dest.mux.Unlock()
+	if len(sent) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(sent))
+	}
+	var grouped *alertMsg
+	for _, msg := range sent {
+		if strings.HasPrefix(msg.uniqueId, "Group_") {
+			grouped = msg
+		}
+	}
+	if grouped == nil {
+		t.Fatal("expected a grouped message")
+	}
+	if grouped.chain != "osmosis, juno, stargaze" || !strings.HasPrefix(grouped.message, "3 RPCNodeDown firing on osmosis, juno, stargaze:\n• osmosis: RPC node") ||
+		grouped.uniqueId != "Group_kind=RPCNodeDown,node_host=rpc.provider.com" {
+		t.Errorf("unexpected grouped message %q %q %q", grouped.chain, grouped.uniqueId, grouped.message)
+	}
+	for _, chain := range []string{"osmosis", "juno", "stargaze"} {
+		if _, ok := alarms.sentFor("discord")["RPCNodeDown_https://rpc.provider.com:443/"+chain]; !ok {
+			t.Errorf("the alert on %s should be recorded as sent", chain)
+		}
+	}
+	if len(outbox.snapshot().Pending) != 0 {
+		t.Error("grouped notifications should leave the outbox")
+	}
+}
+
+func TestGroupingSeparatesResolutions(t *testing.T) {
+	g := &GroupingConfig{Window: time.Second}
+	fire, resolve := testNodeDownMsg("osmosis", "https://a", false), testNodeDownMsg("juno", "https://b", true)
+	if g.key(fire) != g.key(resolve) {
+		t.Error("the alert and its resolution should have the same group ID")
+	}
+
+	setupGroupingTest(t, *g)
+	dest := newFlakyNotifier(false)
+	for _, msg := range []*alertMsg{fire, resolve} {
+		e := outbox.reserve(msg, dest)
+		if !groups.add(e) {
+			t.Fatal("the alert should be grouped")
+		}
+	}
+	groups.mux.Lock()
+	defer groups.mux.Unlock()
+	if len(groups.open) != 2 {
+		t.Errorf("alerts and resolutions should be in separate groups, got %d", len(groups.open))
+	}
+}
+
+func TestGroupingNeedsTheSameDestinationSettings(t *testing.T) {
+	setupGroupingTest(t, GroupingConfig{Window: time.Second, Destinations: []string{"discord"}})
+	dest := newFlakyNotifier(false)
+	a, b := testNodeDownMsg("osmosis", "https://a", false), testNodeDownMsg("juno", "https://b", false)
+	a.alertConfig.Discord.Webhook = "https://discord.com/api/webhooks/1"
+	b.alertConfig.Discord.Webhook = "https://discord.com/api/webhooks/2"
+	for _, msg := range []*alertMsg{a, b} {
+		groups.add(outbox.reserve(msg, dest))
+	}
+	groups.mux.Lock()
+	defer groups.mux.Unlock()
+	if len(groups.open) != 2 {
+		t.Errorf("chains with different webhooks should not be grouped, got %d groups", len(groups.open))
+	}
+	if td.Grouping.applies(a, "slack") {
+		t.Error("grouping should only apply to its destinations")
+	}
+}
+
+func TestGroupingSkipsDestinationsResolvedByID(t *testing.T) {
+	g := &GroupingConfig{Window: time.Second}
+	msg := testNodeDownMsg("osmosis", "https://a", false)
+	for _, dest := range []string{"pagerduty", "opsgenie", "alertmanager", "webhook", "pagerduty@escalation-1"} {
+		if g.applies(msg, dest) {
+			t.Errorf("%s should get one message per alert", dest)
+		}
+	}
+	if !g.applies(msg, "slack") {
+		t.Error("slack messages should be grouped")
+	}
+}
+
+func TestValidateGrouping(t *testing.T) {
+	fatal, problems := validateGrouping(&GroupingConfig{
+		Window:       -time.Second,
+		By:           []string{"kind", "label:proposal_id", "host", "label:"},
+		AlertTypes:   []string{"Unknown"},
+		Destinations: []string{"carrier-pigeon", "pagerduty"},
+	})
+	if !fatal || len(problems) != 6 {
+		t.Errorf("unexpected problems %v", problems)
+	}
+}
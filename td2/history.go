@@ -0,0 +1,322 @@
+package tenderduty
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// HistoryConfig controls the alert history, a record of every alert that fired or resolved and where it was sent.
+type HistoryConfig struct {
+	// Enabled turns the history on, it is off by default
+	Enabled *bool `yaml:"enabled"`
+	// Path is the directory of the history database, .tenderduty-history next to the state file by default
+	Path string `yaml:"path"`
+	// Retention is how long events are kept, 9600h (400 days) by default
+	Retention time.Duration `yaml:"retention"`
+}
+
+// historySettings returns the history config with the defaults filled in.
+func historySettings(stateFile string) HistoryConfig {
+	h := HistoryConfig{}
+	if td != nil {
+		h = td.History
+	}
+	if h.Path == "" {
+		h.Path = filepath.Join(filepath.Dir(stateFile), ".tenderduty-history")
+	}
+	if h.Retention <= 0 {
+		h.Retention = 400 * 24 * time.Hour
+	}
+	return h
+}
+
+func (h HistoryConfig) enabled() bool {
+	return boolVal(h.Enabled)
+}
+
+// HistoryEvent is an alert firing or resolving.
+type HistoryEvent struct {
+	ID       string    `json:"id"`
+	Time     time.Time `json:"time"`
+	Chain    string    `json:"chain"`
+	ChainID  string    `json:"chain_id"`
+	AlertID  string    `json:"alert_id"`
+	Kind     alertKind `json:"kind"`
+	Severity string    `json:"severity"`
+	Message  string    `json:"message"`
+	Resolved bool      `json:"resolved"`
+	// FiredAt and DurationSeconds tell how long the alert was active, they are set on resolutions
+	FiredAt         *time.Time        `json:"fired_at,omitempty"`
+	DurationSeconds float64           `json:"duration_seconds,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Value           float64           `json:"value,omitempty"`
+	Threshold       float64           `json:"threshold,omitempty"`
+	// Deliveries are the attempts to notify destinations, including retries, reminders and escalations
+	Deliveries []HistoryDelivery `json:"deliveries"`
+}
+
+// HistoryDelivery is the result of sending an event to a destination.
+type HistoryDelivery struct {
+	Destination string    `json:"destination"`
+	Time        time.Time `json:"time"`
+	Delivered   bool      `json:"delivered"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// historyStore keeps the events in a leveldb database. The keys start with the time of the event so they can be
+// queried by time.
+type historyStore struct {
+	mux sync.Mutex
+	// db is nil once the database is closed, the store does nothing then
+	db *leveldb.DB
+}
+
+// history is nil when the history is disabled or could not be opened, its methods do nothing then.
+var history *historyStore
+
+const historyPrefix = "event/"
+
+func openHistory(path string) (*historyStore, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &historyStore{db: db}, nil
+}
+
+// historyID returns a new event ID, its hex encoded time sorts in the order of the events.
+func historyID(t time.Time) string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%016x%s", t.UnixNano(), hex.EncodeToString(b))
+}
+
+// record saves an alert that fired or resolved and returns the ID of the event. For a resolution firedAt is when the
+// alert started.
+func (h *historyStore) record(msg *alertMsg, firedAt time.Time) string {
+	if h == nil {
+		return ""
+	}
+	now := time.Now()
+	e := &HistoryEvent{
+		ID:         historyID(now),
+		Time:       now,
+		Chain:      msg.configName,
+		ChainID:    msg.chainId,
+		AlertID:    msg.uniqueId,
+		Kind:       msg.kind,
+		Severity:   msg.severity,
+		Message:    msg.message,
+		Resolved:   msg.resolved,
+		Labels:     msg.labels,
+		Value:      msg.value,
+		Threshold:  msg.threshold,
+		Deliveries: make([]HistoryDelivery, 0),
+	}
+	if msg.resolved && !firedAt.IsZero() {
+		e.FiredAt = &firedAt
+		e.DurationSeconds = now.Sub(firedAt).Seconds()
+	}
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	if h.db == nil {
+		return ""
+	}
+	if err := h.put(e); err != nil {
+		l(slog.LevelWarn, "could not save alert history:", err.Error())
+		return ""
+	}
+	return e.ID
+}
+
+// delivered adds the result of a delivery to an event.
+func (h *historyStore) delivered(eventID, destination string, err error) {
+	if h == nil || eventID == "" {
+		return
+	}
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	if h.db == nil {
+		return
+	}
+	b, e := h.db.Get([]byte(historyPrefix+eventID), nil)
+	if e != nil {
+		// the event was pruned, or the history was reset
+		return
+	}
+	event := &HistoryEvent{}
+	if e = json.Unmarshal(b, event); e != nil {
+		return
+	}
+	d := HistoryDelivery{Destination: destination, Time: time.Now(), Delivered: err == nil}
+	if err != nil {
+		d.Error = err.Error()
+	}
+	event.Deliveries = append(event.Deliveries, d)
+	if e = h.put(event); e != nil {
+		l(slog.LevelWarn, "could not save alert history:", e.Error())
+	}
+}
+
+// put saves an event, the caller must hold mux.
+func (h *historyStore) put(e *HistoryEvent) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return h.db.Put([]byte(historyPrefix+e.ID), b, nil)
+}
+
+// historyQuery selects events, empty fields match everything.
+type historyQuery struct {
+	Chain string
+	Kind  string
+	Since time.Time
+	Limit int
+}
+
+// query returns the matching events, oldest first.
+func (h *historyStore) query(q historyQuery) ([]*HistoryEvent, error) {
+	events := make([]*HistoryEvent, 0)
+	if h == nil {
+		return events, nil
+	}
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	if h.db == nil {
+		return events, nil
+	}
+	start := historyPrefix
+	if !q.Since.IsZero() {
+		start += fmt.Sprintf("%016x", q.Since.UnixNano())
+	}
+	iter := h.db.NewIterator(&util.Range{Start: []byte(start), Limit: util.BytesPrefix([]byte(historyPrefix)).Limit}, nil)
+	defer iter.Release()
+	for iter.Next() {
+		e := &HistoryEvent{}
+		if err := json.Unmarshal(iter.Value(), e); err != nil {
+			continue
+		}
+		if q.Chain != "" && q.Chain != e.Chain && q.Chain != e.ChainID {
+			continue
+		}
+		if q.Kind != "" && q.Kind != string(e.Kind) {
+			continue
+		}
+		events = append(events, e)
+		if q.Limit > 0 && len(events) >= q.Limit {
+			break
+		}
+	}
+	return events, iter.Error()
+}
+
+// prune deletes the events from before a time.
+func (h *historyStore) prune(before time.Time) (int, error) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	if h.db == nil {
+		return 0, nil
+	}
+	iter := h.db.NewIterator(&util.Range{
+		Start: []byte(historyPrefix),
+		Limit: []byte(historyPrefix + fmt.Sprintf("%016x", before.UnixNano())),
+	}, nil)
+	defer iter.Release()
+	batch := new(leveldb.Batch)
+	for iter.Next() {
+		batch.Delete(append([]byte{}, iter.Key()...))
+	}
+	if err := iter.Error(); err != nil {
+		return 0, err
+	}
+	return batch.Len(), h.db.Write(batch, nil)
+}
+
+// run prunes old events once a day, and closes the database when the context is cancelled.
+func (h *historyStore) run(ctx context.Context, retention time.Duration) {
+	tick := time.NewTicker(24 * time.Hour)
+	defer tick.Stop()
+	for {
+		if n, err := h.prune(time.Now().Add(-retention)); err != nil {
+			l(slog.LevelWarn, "could not prune alert history:", err.Error())
+		} else if n > 0 {
+			l(slog.LevelInfo, fmt.Sprintf("🗄 pruned %d alert history events", n))
+		}
+		select {
+		case <-tick.C:
+		case <-ctx.Done():
+			h.close()
+			return
+		}
+	}
+}
+
+// close closes the database, alerts raised while shutting down are no longer recorded.
+func (h *historyStore) close() {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	if h.db != nil {
+		_ = h.db.Close()
+		h.db = nil
+	}
+}
+
+// the most events returned by the API, unless a lower limit is asked for
+const maxHistoryEvents = 10000
+
+// historyHandler serves the alert history, filtered by the chain, since and kind query parameters. since is an
+// RFC3339 time or a duration such as 720h.
+func historyHandler(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		writer.Header().Set("Allow", "GET")
+		http.Error(writer, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if td.HideLogs && !apiAuthorized(request) {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	params := request.URL.Query()
+	q := historyQuery{Chain: params.Get("chain"), Kind: params.Get("kind"), Limit: maxHistoryEvents}
+	if since := params.Get("since"); since != "" {
+		if t, err := time.Parse(time.RFC3339, since); err == nil {
+			q.Since = t
+		} else if d, err := time.ParseDuration(since); err == nil {
+			q.Since = time.Now().Add(-d)
+		} else {
+			http.Error(writer, "since must be an RFC3339 time or a duration", http.StatusBadRequest)
+			return
+		}
+	}
+	if limit := params.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			http.Error(writer, "limit must be a positive number", http.StatusBadRequest)
+			return
+		}
+		q.Limit = min(n, maxHistoryEvents)
+	}
+	if history == nil {
+		http.Error(writer, "the alert history is disabled", http.StatusNotFound)
+		return
+	}
+	events, err := history.query(q)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(writer, http.StatusOK, events)
+}
@@ -0,0 +1,156 @@
+package tenderduty
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func setupHistoryTest(t *testing.T) {
+	setupOutboxTest(t)
+	original := history
+	h, err := openHistory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	history = h
+	t.Cleanup(func() {
+		h.close()
+		history = original
+	})
+}
+
+func TestHistoryRecordsFireAndResolve(t *testing.T) {
+	setupHistoryTest(t)
+	id := "ValidatorInactive_testval123"
+	details := alertDetails{kind: kindValidatorInactive, labels: map[string]string{"status": "jailed"}}
+
+	td.alert("test-chain", "jailed", "critical", false, &id, details)
+	fired := <-td.alertChan
+	if fired.eventID == "" {
+		t.Fatal("a new alert should have a history event")
+	}
+	history.delivered(fired.eventID, "discord", errors.New("destination is down"))
+	history.delivered(fired.eventID, "discord", nil)
+
+	// raising the same alert again belongs to the same event
+	td.alert("test-chain", "jailed", "critical", false, &id, details)
+	if again := <-td.alertChan; again.eventID != fired.eventID {
+		t.Errorf("expected event %s, got %s", fired.eventID, again.eventID)
+	}
+
+	td.alert("test-chain", "jailed", "critical", true, &id, details)
+	resolved := <-td.alertChan
+	if resolved.eventID == "" || resolved.eventID == fired.eventID {
+		t.Fatal("the resolution should have its own history event")
+	}
+	// resolving an alert that is not active is not an event
+	td.alert("test-chain", "jailed", "critical", true, &id, details)
+	if msg := <-td.alertChan; msg.eventID != "" {
+		t.Error("an inactive alert resolving should not be recorded")
+	}
+
+	events, err := history.query(historyQuery{Chain: "test-chain-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	fire, resolve := events[0], events[1]
+	if fire.Resolved || fire.Kind != kindValidatorInactive || fire.Labels["status"] != "jailed" || len(fire.Deliveries) != 2 ||
+		fire.Deliveries[0].Delivered || fire.Deliveries[0].Error != "destination is down" || !fire.Deliveries[1].Delivered {
+		t.Errorf("unexpected fire event %+v", fire)
+	}
+	if !resolve.Resolved || resolve.FiredAt == nil || resolve.DurationSeconds < 0 {
+		t.Errorf("unexpected resolve event %+v", resolve)
+	}
+}
+
+func TestHistoryQueryAndPrune(t *testing.T) {
+	setupHistoryTest(t)
+	history.record(&alertMsg{configName: "osmosis", chainId: "osmosis-1", alertDetails: alertDetails{kind: kindRPCNodeDown}}, time.Time{})
+	history.record(&alertMsg{configName: "juno", chainId: "juno-1", alertDetails: alertDetails{kind: kindChainStalled}}, time.Time{})
+	middle := time.Now()
+	history.record(&alertMsg{configName: "osmosis", chainId: "osmosis-1", alertDetails: alertDetails{kind: kindChainStalled}}, time.Time{})
+
+	for name, tt := range map[string]struct {
+		q        historyQuery
+		expected int
+	}{
+		"everything":     {historyQuery{}, 3},
+		"by chain name":  {historyQuery{Chain: "osmosis"}, 2},
+		"by chain id":    {historyQuery{Chain: "juno-1"}, 1},
+		"by kind":        {historyQuery{Kind: "ChainStalled"}, 2},
+		"since":          {historyQuery{Since: middle}, 1},
+		"limit":          {historyQuery{Limit: 2}, 2},
+		"all the fields": {historyQuery{Chain: "osmosis", Kind: "RPCNodeDown", Since: middle}, 0},
+	} {
+		events, err := history.query(tt.q)
+		if err != nil || len(events) != tt.expected {
+			t.Errorf("%s: expected %d events, got %d (%v)", name, tt.expected, len(events), err)
+		}
+	}
+
+	if n, err := history.prune(middle); err != nil || n != 2 {
+		t.Errorf("expected 2 events pruned, got %d (%v)", n, err)
+	}
+	if events, _ := history.query(historyQuery{}); len(events) != 1 {
+		t.Errorf("expected 1 event left, got %d", len(events))
+	}
+	// deliveries for pruned events are ignored
+	history.delivered(historyID(middle.Add(-time.Hour)), "slack", nil)
+}
+
+func TestHistoryHandler(t *testing.T) {
+	setupHistoryTest(t)
+	history.record(&alertMsg{configName: "osmosis", chainId: "osmosis-1", alertDetails: alertDetails{kind: kindRPCNodeDown}}, time.Time{})
+
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		historyHandler(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+	rec := get("/api/alerts/history?chain=osmosis&since=1h&kind=RPCNodeDown")
+	events := make([]*HistoryEvent, 0)
+	if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &events) != nil || len(events) != 1 {
+		t.Fatalf("unexpected response %d %s", rec.Code, rec.Body.String())
+	}
+	if rec = get("/api/alerts/history?since=" + time.Now().Add(time.Minute).Format(time.RFC3339)); rec.Body.String() != "[]" {
+		t.Errorf("expected no events, got %s", rec.Body.String())
+	}
+	if rec = get("/api/alerts/history?since=yesterday"); rec.Code != http.StatusBadRequest {
+		t.Errorf("an invalid since should be a bad request, got %d", rec.Code)
+	}
+
+	td.HideLogs = true
+	if rec = get("/api/alerts/history"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("hidden logs need a token, got %d", rec.Code)
+	}
+}
+
+func TestHistorySettings(t *testing.T) {
+	setupOutboxTest(t)
+	settings := historySettings("/var/lib/tenderduty/state.json")
+	if settings.enabled() || settings.Path != "/var/lib/tenderduty/.tenderduty-history" || settings.Retention != 400*24*time.Hour {
+		t.Errorf("unexpected defaults %+v", settings)
+	}
+	td.History = HistoryConfig{Enabled: boolPtr(true), Path: "/data/history"}
+	if settings = historySettings("state.json"); !settings.enabled() || settings.Path != "/data/history" {
+		t.Errorf("unexpected settings %+v", settings)
+	}
+}
+
+func TestHistoryClosed(t *testing.T) {
+	setupHistoryTest(t)
+	history.close()
+	if id := history.record(testOutboxMsg(false), time.Time{}); id != "" {
+		t.Error("a closed history should not record events")
+	}
+	if events, err := history.query(historyQuery{}); err != nil || len(events) != 0 {
+		t.Errorf("a closed history should have no events, got %v %v", events, err)
+	}
+}
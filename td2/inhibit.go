@@ -0,0 +1,109 @@
+package tenderduty
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// InhibitRule suppresses notifications for dependent alerts on a chain while a parent alert is active on the same
+// chain. Both Source and Targets are matched as prefixes of the alert ID, for example ChainStalled.
+type InhibitRule struct {
+	Source  string   `yaml:"source"`
+	Targets []string `yaml:"targets"`
+}
+
+// defaultInhibitRules cover the alerts that are expected to follow when a chain halts or all of its RPC nodes go
+// down, these are only noise while the parent alert is active.
+var defaultInhibitRules = []InhibitRule{
+	{
+		Source: "ChainStalled",
+		Targets: []string{"ConsecutiveBlocksMissed", "PercentageBlocksMissed", "ConsecutiveEmptyBlocks", "PercentageEmptyBlocks",
+			"MissedProposals"},
+	},
+	{
+		Source: "NoRPCEndpoints",
+		Targets: []string{"RPCNodeDown", "ChainStalled", "ConsecutiveBlocksMissed", "PercentageBlocksMissed",
+			"ConsecutiveEmptyBlocks", "PercentageEmptyBlocks", "MissedProposals"},
+	},
+}
+
+// inhibitRules are the rules in effect, the defaults merged with the inhibit_rules from the config.
+var inhibitRules = defaultInhibitRules
+
+// mergeInhibitRules overrides the default rules with the configured ones. A configured rule replaces the default
+// rule with the same source, and a rule without targets removes it.
+func mergeInhibitRules(defaults, configured []InhibitRule) []InhibitRule {
+	merged := make([]InhibitRule, 0, len(defaults)+len(configured))
+	overridden := make(map[string]bool)
+	for _, r := range configured {
+		overridden[r.Source] = true
+	}
+	for _, r := range defaults {
+		if !overridden[r.Source] {
+			merged = append(merged, r)
+		}
+	}
+	for _, r := range configured {
+		if len(r.Targets) > 0 {
+			merged = append(merged, r)
+		}
+	}
+	return merged
+}
+
+// inhibitedBy returns the ID of an active alert on the same chain that inhibits the alert, or an empty string. The
+// caller must hold notifyMux.
+func (a *alarmCache) inhibitedBy(configName, alertID string) string {
+	for _, rule := range inhibitRules {
+		if !hasAnyPrefix(alertID, rule.Targets) {
+			continue
+		}
+		for active := range a.AllAlarms[configName] {
+			if active != alertID && strings.HasPrefix(active, rule.Source) {
+				return active
+			}
+		}
+	}
+	return ""
+}
+
+// inhibit remembers an alert that was not sent because of an inhibiting alert, so it can be delivered if it is
+// still active once the parent resolves. The caller must hold notifyMux.
+func (a *alarmCache) inhibit(msg *alertMsg) {
+	if a.inhibited == nil {
+		a.inhibited = make(map[string]map[string]*alertMsg)
+	}
+	if a.inhibited[msg.configName] == nil {
+		a.inhibited[msg.configName] = make(map[string]*alertMsg)
+	}
+	a.inhibited[msg.configName][msg.uniqueId] = msg
+}
+
+// releaseInhibited returns the inhibited alerts on a chain that are still active but no longer inhibited, these
+// need to be delivered now. The caller must hold notifyMux.
+func (a *alarmCache) releaseInhibited(configName string) []*alertMsg {
+	released := make([]*alertMsg, 0)
+	for id, msg := range a.inhibited[configName] {
+		if _, active := a.AllAlarms[configName][id]; !active {
+			delete(a.inhibited[configName], id)
+			continue
+		}
+		if a.inhibitedBy(configName, id) != "" {
+			continue
+		}
+		l(slog.LevelInfo, fmt.Sprintf("🔔 alarm on %s (%s) is no longer inhibited", msg.chain, msg.message))
+		delete(a.inhibited[configName], id)
+		released = append(released, msg)
+	}
+	return released
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package tenderduty
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMergeInhibitRules(t *testing.T) {
+	defaults := []InhibitRule{
+		{Source: "ChainStalled", Targets: []string{"ConsecutiveBlocksMissed"}},
+		{Source: "NoRPCEndpoints", Targets: []string{"RPCNodeDown"}},
+	}
+	tests := []struct {
+		name       string
+		configured []InhibitRule
+		expected   []InhibitRule
+	}{
+		{
+			name:     "defaults are used when nothing is configured",
+			expected: defaults,
+		},
+		{
+			name:       "a rule with the same source replaces the default",
+			configured: []InhibitRule{{Source: "ChainStalled", Targets: []string{"PercentageBlocksMissed"}}},
+			expected: []InhibitRule{
+				{Source: "NoRPCEndpoints", Targets: []string{"RPCNodeDown"}},
+				{Source: "ChainStalled", Targets: []string{"PercentageBlocksMissed"}},
+			},
+		},
+		{
+			name:       "a rule without targets removes the default",
+			configured: []InhibitRule{{Source: "NoRPCEndpoints"}},
+			expected:   []InhibitRule{{Source: "ChainStalled", Targets: []string{"ConsecutiveBlocksMissed"}}},
+		},
+		{
+			name:       "new rules are added",
+			configured: []InhibitRule{{Source: "ValidatorInactive", Targets: []string{"ConsecutiveBlocksMissed"}}},
+			expected:   append(append([]InhibitRule{}, defaults...), InhibitRule{Source: "ValidatorInactive", Targets: []string{"ConsecutiveBlocksMissed"}}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := mergeInhibitRules(defaults, tt.configured); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("mergeInhibitRules() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestShouldNotifyInhibited(t *testing.T) {
+	originalRules := inhibitRules
+	newTestAlarmCache(t).AllAlarms = map[string]map[string]alertMsgCache{
+		"test-chain": {"ChainStalled_testval123": {Message: "stalled", SentTime: time.Now()}},
+	}
+	inhibitRules = defaultInhibitRules
+	defer func() { inhibitRules = originalRules }()
+
+	missed := &alertMsg{
+		configName:  "test-chain",
+		uniqueId:    "ConsecutiveBlocksMissed_testval123",
+		severity:    "critical",
+		alertConfig: &AlertConfig{},
+	}
+	if shouldNotify(missed, discordNotifier{}) {
+		t.Error("alert should be inhibited while the chain is stalled")
+	}
+	if alarms.inhibited["test-chain"][missed.uniqueId] == nil {
+		t.Error("inhibited alert should be remembered")
+	}
+
+	// the parent itself is not inhibited
+	stalled := &alertMsg{configName: "test-chain", uniqueId: "ChainStalled_testval123", severity: "critical", alertConfig: &AlertConfig{}}
+	if !shouldNotify(stalled, discordNotifier{}) {
+		t.Error("the inhibiting alert should notify")
+	}
+
+	// alerts on other chains are not affected
+	other := &alertMsg{configName: "other-chain", uniqueId: "ConsecutiveBlocksMissed_otherval456", severity: "critical", alertConfig: &AlertConfig{}}
+	if !shouldNotify(other, discordNotifier{}) {
+		t.Error("alert on another chain should notify")
+	}
+
+	// clearing while inhibited must not send a resolution
+	resolved := *missed
+	resolved.resolved = true
+	resolved.inhibited = true
+	if shouldNotify(&resolved, discordNotifier{}) {
+		t.Error("inhibited alert should not send a resolution")
+	}
+}
+
+func TestInhibitedAlertReleasedWhenParentResolves(t *testing.T) {
+	originalTd, originalRules := td, inhibitRules
+	td = createTestConfig()
+	newTestAlarmCache(t)
+	inhibitRules = defaultInhibitRules
+	defer func() { td, inhibitRules = originalTd, originalRules }()
+
+	receive := func() *alertMsg {
+		select {
+		case msg := <-td.alertChan:
+			return msg
+		case <-time.After(time.Second):
+			t.Fatal("expected an alert on the channel")
+		}
+		return nil
+	}
+
+	stalledID, missedID, percentID := "ChainStalled_testval123", "ConsecutiveBlocksMissed_testval123", "PercentageBlocksMissed_testval123"
+	td.alert("test-chain", "stalled", "critical", false, &stalledID, alertDetails{})
+	notifyAndRecord(receive(), discordNotifier{})
+	td.alert("test-chain", "missed blocks", "critical", false, &missedID, alertDetails{})
+	if notifyAndRecord(receive(), discordNotifier{}) {
+		t.Fatal("missed blocks should be inhibited")
+	}
+	td.alert("test-chain", "missed percentage", "critical", false, &percentID, alertDetails{})
+	if notifyAndRecord(receive(), discordNotifier{}) {
+		t.Fatal("missed percentage should be inhibited")
+	}
+
+	// the percentage alert clears while inhibited, it should not be delivered later
+	td.alert("test-chain", "missed percentage", "critical", true, &percentID, alertDetails{})
+	if notifyAndRecord(receive(), discordNotifier{}) {
+		t.Error("inhibited alert should not send a resolution")
+	}
+
+	// once the chain resumes the alert that is still active is delivered
+	td.alert("test-chain", "stalled", "critical", true, &stalledID, alertDetails{})
+	if !notifyAndRecord(receive(), discordNotifier{}) {
+		t.Error("resolution of the parent should notify")
+	}
+	released := receive()
+	if released.uniqueId != missedID || released.resolved {
+		t.Fatalf("expected the missed blocks alert to be released, got %s resolved=%v", released.uniqueId, released.resolved)
+	}
+	if !notifyAndRecord(released, discordNotifier{}) {
+		t.Error("released alert should notify")
+	}
+	select {
+	case msg := <-td.alertChan:
+		t.Errorf("unexpected alert %s", msg.uniqueId)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// and it resolves normally
+	td.alert("test-chain", "missed blocks", "critical", true, &missedID, alertDetails{})
+	if !notifyAndRecord(receive(), discordNotifier{}) {
+		t.Error("resolution of the released alert should notify")
+	}
+}
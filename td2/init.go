@@ -39,24 +39,11 @@ func init() {
 	// use a channel for logging, two reasons: several logs could hit at once (formatting,) and to broadcast
 	// messages to the monitoring dashboard
 	go func() {
-		for msg := range logs {
-			parts, ok := msg.([]any)
-			if !ok || len(parts) == 0 {
-				continue
-			}
-			level, ok := parts[0].(slog.Level)
-			if !ok {
-				level = slog.LevelInfo
-			} else {
-				parts = parts[1:]
-			}
-			if len(parts) == 0 {
-				continue
-			}
-			msgStr := strings.TrimRight(strings.TrimLeft(fmt.Sprint(parts...), "["), "]")
-			slog.Log(context.Background(), level, "tenderduty | "+msgStr)
-			if td.EnableDash && !td.HideLogs && td.logChan != nil {
-				td.logChan <- dash.LogMessage{
+		for entry := range logs {
+			msgStr := strings.TrimRight(strings.TrimLeft(fmt.Sprint(entry.parts...), "["), "]")
+			slog.Log(context.Background(), entry.level, "tenderduty | "+msgStr)
+			if entry.dashLogs != nil {
+				entry.dashLogs <- dash.LogMessage{
 					MsgType: "log",
 					Ts:      time.Now().UTC().Unix(),
 					Msg:     msgStr,
@@ -66,7 +53,15 @@ func init() {
 	}()
 }
 
-var logs = make(chan any)
+// logEntry is a message for the logging goroutine. The dashboard's log channel is looked up when the message is
+// logged, so the goroutine does not need to read the config.
+type logEntry struct {
+	level    slog.Level
+	parts    []any
+	dashLogs chan dash.LogMessage
+}
+
+var logs = make(chan logEntry)
 
 func l(v ...any) {
 	if len(v) == 0 {
@@ -84,5 +79,9 @@ func l(v ...any) {
 	if len(v) == 0 {
 		return
 	}
-	logs <- append([]any{level}, v...)
+	entry := logEntry{level: level, parts: v}
+	if td.EnableDash && !td.HideLogs {
+		entry.dashLogs = td.logChan
+	}
+	logs <- entry
 }
@@ -0,0 +1,98 @@
+package tenderduty
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func init() {
+	registerNotifier(alertmanagerNotifier{})
+}
+
+// alertmanagerNotifier posts alerts to a Prometheus Alertmanager using the /api/v2/alerts endpoint.
+type alertmanagerNotifier struct{}
+
+func (alertmanagerNotifier) Name() string { return "alertmanager" }
+
+func (alertmanagerNotifier) Enabled(cfg *AlertConfig) bool { return boolVal(cfg.Alertmanager.Enabled) }
+
+func (alertmanagerNotifier) SeverityThreshold(cfg *AlertConfig) string {
+	return cfg.Alertmanager.SeverityThreshold
+}
+
+// AlertmanagerAlert is a postable alert for the Alertmanager v2 API
+type AlertmanagerAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     string            `json:"startsAt,omitempty"`
+	EndsAt       string            `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+func (alertmanagerNotifier) Send(msg *alertMsg) (err error) {
+	cfg := msg.alertConfig.Alertmanager
+	if cfg.URL == "" {
+		return errors.New("alertmanager url is not configured")
+	}
+
+	// Alertmanager de-duplicates on the label set, so these must be identical for the firing and resolved posts.
+	labels := map[string]string{
+		"alertname":       msg.uniqueId,
+		"chain":           msg.chain,
+		"chain_name":      msg.chainName,
+		"valoper_address": msg.valoperAddress,
+		"valcons_address": msg.valconsAddress,
+		"severity":        msg.severity,
+		"source":          "tenderduty",
+	}
+	for k, v := range cfg.Labels {
+		labels[k] = v
+	}
+
+	now := time.Now().UTC()
+	alert := AlertmanagerAlert{
+		Labels: labels,
+		Annotations: map[string]string{
+			"summary":     msg.message,
+			"description": msg.message,
+		},
+	}
+	if msg.resolved {
+		alert.EndsAt = now.Format(time.RFC3339)
+	} else {
+		// tenderduty only posts an alert once, without endsAt Alertmanager would resolve it after its
+		// resolve_timeout, so keep it open until we send the resolution or it goes stale.
+		alert.StartsAt = now.Format(time.RFC3339)
+		alert.EndsAt = now.Add(staleHours * time.Hour).Format(time.RFC3339)
+	}
+
+	data, err := json.Marshal([]AlertmanagerAlert{alert})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", strings.TrimRight(cfg.URL, "/")+"/api/v2/alerts", bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if cfg.Username != "" {
+		req.SetBasicAuth(cfg.Username, cfg.Password)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("alertmanager returned status %d for %s", resp.StatusCode, msg.chain)
+	}
+	return nil
+}
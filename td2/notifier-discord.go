@@ -0,0 +1,177 @@
+package tenderduty
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+)
+
+func init() {
+	registerNotifier(discordNotifier{})
+}
+
+// discordNotifier posts alerts to a Discord webhook.
+type discordNotifier struct{}
+
+func (discordNotifier) Name() string { return "discord" }
+
+func (discordNotifier) Enabled(cfg *AlertConfig) bool { return boolVal(cfg.Discord.Enabled) }
+
+func (discordNotifier) SeverityThreshold(cfg *AlertConfig) string {
+	return cfg.Discord.SeverityThreshold
+}
+
+func (discordNotifier) Send(msg *alertMsg) (err error) {
+	discPost := buildDiscordMessage(msg)
+	client := &http.Client{}
+	data, err := json.MarshalIndent(discPost, "", "  ")
+	if err != nil {
+		l(slog.LevelWarn, "⚠️ Could not notify discord!", err)
+		return err
+	}
+
+	webhook := msg.alertConfig.Discord.Webhook
+	if len(discPost.Components) > 0 {
+		// without this Discord drops the buttons
+		webhook, err = withQuery(webhook, "with_components", "true")
+		if err != nil {
+			l(slog.LevelWarn, "⚠️ Could not notify discord!", err)
+			return err
+		}
+	}
+
+	req, err := http.NewRequest("POST", webhook, bytes.NewBuffer(data))
+	if err != nil {
+		l(slog.LevelWarn, "⚠️ Could not notify discord!", err)
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		l(slog.LevelWarn, "⚠️ Could not notify discord!", err)
+		return err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != 204 {
+		slog.Warn("discord webhook returned non-success response", "status", resp.StatusCode)
+		l(slog.LevelWarn, "⚠️ Could not notify discord! Returned", resp.StatusCode)
+		return fmt.Errorf("discord returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+type DiscordMessage struct {
+	Username   string             `json:"username,omitempty"`
+	AvatarUrl  string             `json:"avatar_url,omitempty"`
+	Content    string             `json:"content"`
+	Embeds     []DiscordEmbed     `json:"embeds,omitempty"`
+	Components []DiscordComponent `json:"components,omitempty"`
+}
+
+// DiscordComponent is an action row, or a button inside one.
+type DiscordComponent struct {
+	Type       int                `json:"type"`
+	Style      int                `json:"style,omitempty"`
+	Label      string             `json:"label,omitempty"`
+	CustomID   string             `json:"custom_id,omitempty"`
+	Components []DiscordComponent `json:"components,omitempty"`
+}
+
+// Discord component types and button styles
+const (
+	discordActionRow       = 1
+	discordButton          = 2
+	discordButtonPrimary   = 1
+	discordButtonSecondary = 2
+	discordButtonSuccess   = 3
+)
+
+// a button's custom_id can be at most 100 characters
+const maxDiscordCustomID = 100
+
+type DiscordEmbed struct {
+	Title       string `json:"title,omitempty"`
+	Url         string `json:"url,omitempty"`
+	Description string `json:"description"`
+	Color       uint   `json:"color"`
+}
+
+func buildDiscordMessage(msg *alertMsg) *DiscordMessage {
+	prefix := "🚨 ALERT: "
+	if msg.resolved {
+		prefix = "💜 Resolved: "
+	}
+	dm := &DiscordMessage{
+		Username: "Tenderduty",
+		Content:  prefix + msg.chain,
+		Embeds: []DiscordEmbed{{
+			Description: msg.message,
+		}},
+	}
+	if msg.alertConfig != nil && boolVal(msg.alertConfig.Discord.Interactive) && !msg.resolved {
+		buttons := make([]DiscordComponent, 0, len(alertActions))
+		for _, a := range alertActions {
+			button := DiscordComponent{Type: discordButton, Style: discordButtonSecondary, Label: a.label, CustomID: a.action + ":" + actionValue(msg)}
+			switch a.action {
+			case actionAck:
+				button.Style = discordButtonPrimary
+			case actionResolve:
+				button.Style = discordButtonSuccess
+			}
+			if len(button.CustomID) > maxDiscordCustomID {
+				return dm
+			}
+			buttons = append(buttons, button)
+		}
+		dm.Components = []DiscordComponent{{Type: discordActionRow, Components: buttons}}
+	}
+	return dm
+}
+
+// withQuery adds a query parameter to a URL.
+func withQuery(rawURL, key, value string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set(key, value)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// a Discord message can have at most 10 embeds
+const maxDiscordEmbeds = 10
+
+func (discordNotifier) sendReport(report *DigestReport, cfg *AlertConfig) error {
+	for _, dm := range buildDiscordReport(report) {
+		if err := postJSON(cfg.Discord.Webhook, dm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildDiscordReport has an embed for each chain, split over several messages when there are many chains.
+func buildDiscordReport(report *DigestReport) []*DiscordMessage {
+	messages := []*DiscordMessage{{Username: "Tenderduty", Content: report.title()}}
+	for i := range report.Chains {
+		dm := messages[len(messages)-1]
+		if len(dm.Embeds) == maxDiscordEmbeds {
+			dm = &DiscordMessage{Username: "Tenderduty"}
+			messages = append(messages, dm)
+		}
+		dc := &report.Chains[i]
+		color := uint(0x2ecc71)
+		if dc.AlertsFired > 0 || dc.ActiveAlerts > 0 {
+			color = 0xf1c40f
+		}
+		dm.Embeds = append(dm.Embeds, DiscordEmbed{Title: dc.heading(), Description: dc.text(), Color: color})
+	}
+	return messages
+}
@@ -0,0 +1,160 @@
+package tenderduty
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	registerNotifier(emailNotifier{})
+}
+
+// smtpDial opens the connection to the mail server, implicitTLS is used for SMTPS (usually port 465). It is a
+// variable so tests can swap in a local SMTP sink.
+var smtpDial = func(addr string, implicitTLS bool, tlsConfig *tls.Config) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	if implicitTLS {
+		return tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	}
+	return dialer.Dial("tcp", addr)
+}
+
+// emailNotifier sends alerts as plain text email over SMTP.
+type emailNotifier struct{}
+
+func (emailNotifier) Name() string { return "email" }
+
+func (emailNotifier) Enabled(cfg *AlertConfig) bool { return boolVal(cfg.Email.Enabled) }
+
+func (emailNotifier) SeverityThreshold(cfg *AlertConfig) string {
+	return cfg.Email.SeverityThreshold
+}
+
+func (emailNotifier) Send(msg *alertMsg) (err error) {
+	cfg := msg.alertConfig.Email
+	return sendMail(cfg, buildEmailMessage(msg, cfg.From, cfg.To))
+}
+
+// sendMail delivers an RFC 5322 message to the recipients of an email config.
+func sendMail(cfg EmailConfig, message []byte) (err error) {
+	if cfg.Host == "" || cfg.From == "" || len(cfg.To) == 0 {
+		return errors.New("email host, from and to must be configured")
+	}
+	port := cfg.Port
+	mode := strings.ToLower(cfg.TLS)
+	if mode == "" {
+		mode = "starttls"
+	}
+	if port == 0 {
+		port = 587
+		if mode == "tls" {
+			port = 465
+		}
+	}
+
+	//#nosec G402 -- configurable option
+	tlsConfig := &tls.Config{ServerName: cfg.Host, InsecureSkipVerify: td.TLSSkipVerify}
+	conn, err := smtpDial(net.JoinHostPort(cfg.Host, strconv.Itoa(port)), mode == "tls", tlsConfig)
+	if err != nil {
+		return fmt.Errorf("could not connect to smtp server %s: %w", cfg.Host, err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	c, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	switch mode {
+	case "starttls":
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("smtp server %s does not support STARTTLS", cfg.Host)
+		}
+		if err = c.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	case "tls", "none":
+	default:
+		return fmt.Errorf("unknown email tls mode %s, valid choices are starttls, tls, and none", cfg.TLS)
+	}
+
+	if cfg.Username != "" {
+		if err = c.Auth(smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)); err != nil {
+			return err
+		}
+	}
+	if err = c.Mail(cfg.From); err != nil {
+		return err
+	}
+	for _, rcpt := range cfg.To {
+		if err = c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(message); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// buildEmailMessage renders the RFC 5322 message for an alert.
+func buildEmailMessage(msg *alertMsg, from string, to []string) []byte {
+	prefix := "🚨 ALERT"
+	if msg.resolved {
+		prefix = "💜 Resolved"
+	}
+	subject := fmt.Sprintf("TenderDuty %s: %s", prefix, msg.chain)
+
+	body := &bytes.Buffer{}
+	body.WriteString(msg.message + "\r\n\r\n")
+	for _, field := range [][2]string{
+		{"Chain", msg.chain},
+		{"Valoper", msg.valoperAddress},
+		{"Valcons", msg.valconsAddress},
+		{"Severity", msg.severity},
+		{"Alert ID", msg.uniqueId},
+	} {
+		if field[1] != "" {
+			body.WriteString(fmt.Sprintf("%s: %s\r\n", field[0], field[1]))
+		}
+	}
+
+	return buildEmail(from, to, subject, body.Bytes())
+}
+
+// buildEmail renders a plain text message.
+func buildEmail(from string, to []string, subject string, body []byte) []byte {
+	b := &bytes.Buffer{}
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + strings.Join(to, ", ") + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.Write(body)
+	return b.Bytes()
+}
+
+func (emailNotifier) sendReport(report *DigestReport, cfg *AlertConfig) error {
+	body := strings.ReplaceAll(report.text(), "\n", "\r\n")
+	return sendMail(cfg.Email, buildEmail(cfg.Email.From, cfg.Email.To, report.title(), []byte(body)))
+}
@@ -0,0 +1,85 @@
+package tenderduty
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func init() {
+	registerNotifier(gotifyNotifier{})
+}
+
+// gotifyNotifier pushes alerts to a Gotify server.
+type gotifyNotifier struct{}
+
+func (gotifyNotifier) Name() string { return "gotify" }
+
+func (gotifyNotifier) Enabled(cfg *AlertConfig) bool { return boolVal(cfg.Gotify.Enabled) }
+
+func (gotifyNotifier) SeverityThreshold(cfg *AlertConfig) string {
+	return cfg.Gotify.SeverityThreshold
+}
+
+// GotifyMessage is the JSON body for the Gotify /message endpoint
+type GotifyMessage struct {
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	Priority int    `json:"priority"`
+}
+
+// buildGotifyMessage maps the severity onto Gotify's 0-10 priority: critical is high (8), warning is normal (5) and
+// info is low (2). Resolutions are also sent with low priority.
+func buildGotifyMessage(msg *alertMsg) *GotifyMessage {
+	m := &GotifyMessage{
+		Title:   "🚨 ALERT: " + msg.chain,
+		Message: msg.message,
+	}
+	switch {
+	case msg.resolved:
+		m.Title = "💜 Resolved: " + msg.chain
+		m.Message = "OK: " + msg.message
+		m.Priority = 2
+	case msg.severity == "critical":
+		m.Priority = 8
+	case msg.severity == "warning":
+		m.Priority = 5
+	default:
+		m.Priority = 2
+	}
+	return m
+}
+
+func (gotifyNotifier) Send(msg *alertMsg) (err error) {
+	cfg := msg.alertConfig.Gotify
+	if cfg.Server == "" || cfg.Token == "" {
+		return errors.New("gotify server and token must be configured")
+	}
+	data, err := json.Marshal(buildGotifyMessage(msg))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", strings.TrimRight(cfg.Server, "/")+"/message", bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", cfg.Token)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("could not notify gotify for %s got %d response", msg.chain, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package tenderduty
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+func init() {
+	registerNotifier(matrixNotifier{})
+}
+
+// matrixNotifier sends alerts to a Matrix room using the client-server API.
+type matrixNotifier struct{}
+
+func (matrixNotifier) Name() string { return "matrix" }
+
+func (matrixNotifier) Enabled(cfg *AlertConfig) bool { return boolVal(cfg.Matrix.Enabled) }
+
+func (matrixNotifier) SeverityThreshold(cfg *AlertConfig) string {
+	return cfg.Matrix.SeverityThreshold
+}
+
+// MatrixMessage is an m.room.message event
+type MatrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format,omitempty"`
+	FormattedBody string `json:"formatted_body,omitempty"`
+}
+
+// buildMatrixMessage maps the severity onto the message type: critical and warning alerts are sent as m.text so
+// clients notify, info alerts and resolutions as m.notice which most clients treat as low priority.
+func buildMatrixMessage(msg *alertMsg) *MatrixMessage {
+	prefix := "🚨 ALERT"
+	msgType := "m.text"
+	if msg.resolved {
+		prefix = "💜 Resolved"
+		msgType = "m.notice"
+	} else if msg.severity == "info" {
+		msgType = "m.notice"
+	}
+	mentions := ""
+	if !msg.resolved && len(msg.alertConfig.Matrix.Mentions) > 0 {
+		mentions = " " + strings.Join(msg.alertConfig.Matrix.Mentions, " ")
+	}
+	severity := ""
+	if msg.severity != "" {
+		severity = fmt.Sprintf(" [%s]", strings.ToUpper(msg.severity))
+	}
+	return &MatrixMessage{
+		MsgType: msgType,
+		Body:    fmt.Sprintf("%s%s: %s: %s%s", prefix, severity, msg.chain, msg.message, mentions),
+		Format:  "org.matrix.custom.html",
+		FormattedBody: fmt.Sprintf("<b>%s%s: %s</b><br/>%s%s", prefix, severity, html.EscapeString(msg.chain),
+			html.EscapeString(msg.message), html.EscapeString(mentions)),
+	}
+}
+
+func (matrixNotifier) Send(msg *alertMsg) (err error) {
+	cfg := msg.alertConfig.Matrix
+	if cfg.Homeserver == "" || cfg.RoomID == "" || cfg.AccessToken == "" {
+		return errors.New("matrix homeserver, room_id and access_token must be configured")
+	}
+	data, err := json.Marshal(buildMatrixMessage(msg))
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/tenderduty-%d",
+		strings.TrimRight(cfg.Homeserver, "/"), url.PathEscape(cfg.RoomID), time.Now().UnixNano())
+	req, err := http.NewRequest("PUT", endpoint, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+cfg.AccessToken)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("could not notify matrix for %s got %d response", msg.chain, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package tenderduty
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func init() {
+	registerNotifier(ntfyNotifier{})
+}
+
+const ntfyDefaultServer = "https://ntfy.sh"
+
+// ntfyNotifier publishes alerts to a ntfy topic.
+type ntfyNotifier struct{}
+
+func (ntfyNotifier) Name() string { return "ntfy" }
+
+func (ntfyNotifier) Enabled(cfg *AlertConfig) bool { return boolVal(cfg.Ntfy.Enabled) }
+
+func (ntfyNotifier) SeverityThreshold(cfg *AlertConfig) string {
+	return cfg.Ntfy.SeverityThreshold
+}
+
+// NtfyMessage is the JSON body for publishing to a ntfy server
+type NtfyMessage struct {
+	Topic    string   `json:"topic"`
+	Title    string   `json:"title"`
+	Message  string   `json:"message"`
+	Priority int      `json:"priority"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// buildNtfyMessage maps the severity onto ntfy's 1-5 priority: critical is urgent (5), warning is high (4) and info
+// uses the default (3). Resolutions are sent with low priority (2) so they don't wake anyone up.
+func buildNtfyMessage(msg *alertMsg) *NtfyMessage {
+	m := &NtfyMessage{
+		Topic:   msg.alertConfig.Ntfy.Topic,
+		Title:   "🚨 ALERT: " + msg.chain,
+		Message: msg.message,
+	}
+	switch {
+	case msg.resolved:
+		m.Title = "💜 Resolved: " + msg.chain
+		m.Priority = 2
+		m.Tags = []string{"white_check_mark", "resolved"}
+		return m
+	case msg.severity == "critical":
+		m.Priority = 5
+		m.Tags = []string{"rotating_light"}
+	case msg.severity == "warning":
+		m.Priority = 4
+		m.Tags = []string{"warning"}
+	default:
+		m.Priority = 3
+		m.Tags = []string{"information_source"}
+	}
+	if msg.severity != "" {
+		m.Tags = append(m.Tags, msg.severity)
+	}
+	return m
+}
+
+func (ntfyNotifier) Send(msg *alertMsg) (err error) {
+	cfg := msg.alertConfig.Ntfy
+	if cfg.Topic == "" {
+		return errors.New("ntfy topic must be configured")
+	}
+	server := strings.TrimRight(cfg.Server, "/")
+	if server == "" {
+		server = ntfyDefaultServer
+	}
+	data, err := json.Marshal(buildNtfyMessage(msg))
+	if err != nil {
+		return err
+	}
+
+	// publishing JSON is done against the root of the server, the topic is in the body
+	req, err := http.NewRequest("POST", server, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	switch {
+	case cfg.Token != "":
+		req.Header.Set("Authorization", "Bearer "+cfg.Token)
+	case cfg.Username != "":
+		req.SetBasicAuth(cfg.Username, cfg.Password)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("could not notify ntfy for %s got %d response", msg.chain, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package tenderduty
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+func init() {
+	registerNotifier(opsgenieNotifier{})
+}
+
+const opsgenieDefaultURL = "https://api.opsgenie.com"
+
+// opsgenieNotifier creates and closes alerts through the Opsgenie Alert API v2, using the alert ID as the alias.
+type opsgenieNotifier struct{}
+
+func (opsgenieNotifier) Name() string { return "opsgenie" }
+
+func (opsgenieNotifier) Enabled(cfg *AlertConfig) bool { return boolVal(cfg.Opsgenie.Enabled) }
+
+func (opsgenieNotifier) SeverityThreshold(cfg *AlertConfig) string {
+	return cfg.Opsgenie.SeverityThreshold
+}
+
+// OpsgenieAlert is the body for creating an alert
+type OpsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+	Source      string            `json:"source,omitempty"`
+	Priority    string            `json:"priority,omitempty"`
+}
+
+// OpsgenieClose is the body for closing an alert
+type OpsgenieClose struct {
+	Source string `json:"source,omitempty"`
+	Note   string `json:"note,omitempty"`
+}
+
+// opsgeniePriority maps a tenderduty severity to an Opsgenie priority.
+func opsgeniePriority(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return "P1"
+	case "warning":
+		return "P3"
+	case "info":
+		return "P5"
+	}
+	return "P3"
+}
+
+func (opsgenieNotifier) Send(msg *alertMsg) (err error) {
+	cfg := msg.alertConfig.Opsgenie
+	if cfg.ApiKey == "" {
+		return errors.New("opsgenie api_key is not configured")
+	}
+	base := strings.TrimRight(cfg.ApiURL, "/")
+	if base == "" {
+		base = opsgenieDefaultURL
+	}
+
+	var endpoint string
+	var payload any
+	if msg.resolved {
+		endpoint = fmt.Sprintf("%s/v2/alerts/%s/close?identifierType=alias", base, url.PathEscape(msg.uniqueId))
+		payload = OpsgenieClose{
+			Source: "tenderduty",
+			Note:   msg.message,
+		}
+	} else {
+		// opsgenie truncates messages over 130 characters, the full text is kept in the description
+		summary := msg.message
+		if len([]rune(summary)) > 130 {
+			summary = string([]rune(summary)[:127]) + "..."
+		}
+		payload = OpsgenieAlert{
+			Message:     summary,
+			Alias:       msg.uniqueId,
+			Description: msg.message,
+			Tags:        append([]string{"tenderduty"}, cfg.Tags...),
+			Details: map[string]string{
+				"chain":           msg.chain,
+				"chain_name":      msg.chainName,
+				"valoper_address": msg.valoperAddress,
+				"valcons_address": msg.valconsAddress,
+				"severity":        msg.severity,
+			},
+			Source:   "tenderduty",
+			Priority: opsgeniePriority(msg.severity),
+		}
+		endpoint = base + "/v2/alerts"
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "GenieKey "+cfg.ApiKey)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("opsgenie returned status %d for %s", resp.StatusCode, msg.chain)
+	}
+	return nil
+}
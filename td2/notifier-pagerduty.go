@@ -0,0 +1,75 @@
+package tenderduty
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+)
+
+func init() {
+	registerNotifier(pagerdutyNotifier{})
+}
+
+// pagerdutyNotifier sends alerts to the PagerDuty V2 events API.
+type pagerdutyNotifier struct{}
+
+func (pagerdutyNotifier) Name() string { return "pagerduty" }
+
+func (pagerdutyNotifier) Enabled(cfg *AlertConfig) bool { return boolVal(cfg.Pagerduty.Enabled) }
+
+func (pagerdutyNotifier) SeverityThreshold(cfg *AlertConfig) string {
+	return cfg.Pagerduty.SeverityThreshold
+}
+
+func (pagerdutyNotifier) Send(msg *alertMsg) (err error) {
+	key := msg.alertConfig.Pagerduty.ApiKey
+	// key from the example, don't spam their api
+	if key == "aaaaaaaaaaaabbbbbbbbbbbbbcccccccccccc" {
+		l(slog.LevelWarn, "invalid pagerduty key")
+		return
+	}
+	action := "trigger"
+	if msg.resolved {
+		action = "resolve"
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	_, err = pagerduty.ManageEventWithContext(ctx, pagerduty.V2Event{
+		RoutingKey: key,
+		Action:     action,
+		DedupKey:   msg.uniqueId,
+		Payload: &pagerduty.V2Payload{
+			Summary:  msg.message,
+			Source:   msg.uniqueId,
+			Severity: msg.severity,
+			Class:    string(msg.kind),
+			Details:  pagerdutyDetails(msg),
+		},
+	})
+	return
+}
+
+// pagerdutyDetails are the custom details of an event, so that PagerDuty event rules can use the alert's fields.
+func pagerdutyDetails(msg *alertMsg) map[string]any {
+	details := map[string]any{
+		"kind":            msg.kind,
+		"chain":           msg.configName,
+		"chain_id":        msg.chainId,
+		"valoper_address": msg.valoperAddress,
+		"valcons_address": msg.valconsAddress,
+		"moniker":         msg.moniker,
+	}
+	if len(msg.labels) > 0 {
+		details["labels"] = msg.labels
+	}
+	if msg.alertConfig != nil && msg.alertConfig.Pagerduty.Priorities[string(msg.kind)] != "" {
+		details["priority"] = msg.alertConfig.Pagerduty.Priorities[string(msg.kind)]
+	}
+	if msg.numeric() {
+		details["value"] = msg.value
+		details["threshold"] = msg.threshold
+	}
+	return details
+}
@@ -0,0 +1,136 @@
+package tenderduty
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func init() {
+	registerNotifier(slackNotifier{})
+}
+
+// slackNotifier posts alerts to a Slack incoming webhook.
+type slackNotifier struct{}
+
+func (slackNotifier) Name() string { return "slack" }
+
+func (slackNotifier) Enabled(cfg *AlertConfig) bool { return boolVal(cfg.Slack.Enabled) }
+
+func (slackNotifier) SeverityThreshold(cfg *AlertConfig) string {
+	return cfg.Slack.SeverityThreshold
+}
+
+func (slackNotifier) Send(msg *alertMsg) (err error) {
+	data, err := json.Marshal(buildSlackMessage(msg))
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequest("POST", msg.alertConfig.Slack.Webhook, bytes.NewBuffer(data))
+	if err != nil {
+		return
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("could not notify slack for %s got %d response", msg.chain, resp.StatusCode)
+	}
+
+	return
+}
+
+type SlackMessage struct {
+	Text        string       `json:"text"`
+	Attachments []Attachment `json:"attachments"`
+	Blocks      []SlackBlock `json:"blocks,omitempty"`
+}
+
+// SlackBlock is a Block Kit block, only used for the buttons on interactive alerts.
+type SlackBlock struct {
+	Type     string         `json:"type"`
+	Text     *SlackText     `json:"text,omitempty"`
+	Elements []SlackElement `json:"elements,omitempty"`
+}
+
+type SlackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type SlackElement struct {
+	Type     string    `json:"type"`
+	Text     SlackText `json:"text"`
+	ActionID string    `json:"action_id"`
+	Value    string    `json:"value"`
+	Style    string    `json:"style,omitempty"`
+}
+
+type Attachment struct {
+	Text      string `json:"text"`
+	Color     string `json:"color"`
+	Title     string `json:"title"`
+	TitleLink string `json:"title_link"`
+}
+
+func buildSlackMessage(msg *alertMsg) *SlackMessage {
+	prefix := "🚨 ALERT: "
+	color := "danger"
+	text := msg.message
+	if msg.resolved {
+		text = "OK: " + msg.message
+		prefix = "💜 Resolved: "
+		color = "good"
+	}
+	sm := &SlackMessage{
+		Text: text,
+		Attachments: []Attachment{
+			{
+				Title: fmt.Sprintf("TenderDuty %s %s %s", prefix, msg.chain, strings.Join(msg.alertConfig.Slack.Mentions, " ")),
+				Color: color,
+			},
+		},
+	}
+	if boolVal(msg.alertConfig.Slack.Interactive) && !msg.resolved {
+		// with blocks the text is only used for the notification, so it is repeated in a section
+		buttons := make([]SlackElement, 0, len(alertActions))
+		for _, a := range alertActions {
+			button := SlackElement{Type: "button", Text: SlackText{Type: "plain_text", Text: a.label}, ActionID: a.action, Value: actionValue(msg)}
+			if a.action == actionAck {
+				button.Style = "primary"
+			}
+			buttons = append(buttons, button)
+		}
+		sm.Blocks = []SlackBlock{
+			{Type: "section", Text: &SlackText{Type: "plain_text", Text: text}},
+			{Type: "actions", Elements: buttons},
+		}
+	}
+	return sm
+}
+
+func (slackNotifier) sendReport(report *DigestReport, cfg *AlertConfig) error {
+	return postJSON(cfg.Slack.Webhook, buildSlackReport(report))
+}
+
+// buildSlackReport has an attachment for each chain, chains that had alerts are highlighted.
+func buildSlackReport(report *DigestReport) *SlackMessage {
+	sm := &SlackMessage{Text: report.title(), Attachments: make([]Attachment, 0, len(report.Chains))}
+	for i := range report.Chains {
+		dc := &report.Chains[i]
+		color := "good"
+		if dc.AlertsFired > 0 || dc.ActiveAlerts > 0 {
+			color = "warning"
+		}
+		sm.Attachments = append(sm.Attachments, Attachment{Title: dc.heading(), Text: dc.text(), Color: color})
+	}
+	return sm
+}
@@ -0,0 +1,43 @@
+package tenderduty
+
+import (
+	"fmt"
+	"log/slog"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func init() {
+	registerNotifier(telegramNotifier{})
+}
+
+// telegramNotifier sends alerts to a Telegram channel through the bot API.
+type telegramNotifier struct{}
+
+func (telegramNotifier) Name() string { return "telegram" }
+
+func (telegramNotifier) Enabled(cfg *AlertConfig) bool { return boolVal(cfg.Telegram.Enabled) }
+
+func (telegramNotifier) SeverityThreshold(cfg *AlertConfig) string {
+	return cfg.Telegram.SeverityThreshold
+}
+
+func (telegramNotifier) Send(msg *alertMsg) (err error) {
+	bot, err := tgbotapi.NewBotAPI(msg.alertConfig.Telegram.ApiKey)
+	if err != nil {
+		l(slog.LevelWarn, "notify telegram:", err)
+		return
+	}
+
+	prefix := "🚨 ALERT"
+	if msg.resolved {
+		prefix = "💜 Resolved"
+	}
+
+	mc := tgbotapi.NewMessageToChannel(msg.alertConfig.Telegram.Channel, fmt.Sprintf("%s: %s: %s", msg.chain, prefix, msg.message))
+	_, err = bot.Send(mc)
+	if err != nil {
+		l(slog.LevelWarn, "telegram send:", err)
+	}
+	return err
+}
@@ -0,0 +1,136 @@
+package tenderduty
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func init() {
+	registerNotifier(webhookNotifier{})
+}
+
+// webhookNotifier posts alerts to a generic webhook endpoint.
+type webhookNotifier struct{}
+
+func (webhookNotifier) Name() string { return "webhook" }
+
+func (webhookNotifier) Enabled(cfg *AlertConfig) bool { return boolVal(cfg.Webhook.Enabled) }
+
+func (webhookNotifier) SeverityThreshold(cfg *AlertConfig) string {
+	return cfg.Webhook.SeverityThreshold
+}
+
+// WebhookPayload represents the payload sent to a generic webhook endpoint
+// The structure is inspired by Grafana's webhook contact point format
+type WebhookPayload struct {
+	Status   string         `json:"status"`
+	Alerts   []WebhookAlert `json:"alerts"`
+	Version  string         `json:"version"`
+	GroupKey string         `json:"groupKey"`
+}
+
+// WebhookAlert represents a single alert in the webhook payload
+type WebhookAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     string            `json:"startsAt"`
+	EndsAt       string            `json:"endsAt"`
+	Fingerprint  string            `json:"fingerprint"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+func (webhookNotifier) Send(msg *alertMsg) (err error) {
+	// Skip sending resolved messages if disabled in config
+	if msg.resolved && boolVal(msg.alertConfig.Webhook.DisableResolveMessage) {
+		return nil
+	}
+
+	status := "firing"
+	if msg.resolved {
+		status = "resolved"
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	endsAt := "0001-01-01T00:00:00Z"
+	if msg.resolved {
+		endsAt = now
+	}
+
+	alert := WebhookAlert{
+		Status: status,
+		Labels: map[string]string{
+			"alertname":       msg.uniqueId,
+			"alert_kind":      string(msg.kind),
+			"chain":           msg.chain,
+			"chain_id":        msg.chainId,
+			"chain_name":      msg.chainName,
+			"valoper_address": msg.valoperAddress,
+			"valcons_address": msg.valconsAddress,
+			"severity":        msg.severity,
+			"source":          "tenderduty",
+		},
+		Annotations: map[string]string{
+			"summary":     msg.message,
+			"description": msg.message,
+		},
+		StartsAt:    now,
+		EndsAt:      endsAt,
+		Fingerprint: msg.uniqueId,
+	}
+	// the alert's own labels can't replace the ones above
+	for k, v := range msg.labels {
+		if _, ok := alert.Labels[k]; !ok {
+			alert.Labels[k] = v
+		}
+	}
+	if msg.numeric() {
+		alert.Annotations["value"] = strconv.FormatFloat(msg.value, 'f', -1, 64)
+		alert.Annotations["threshold"] = strconv.FormatFloat(msg.threshold, 'f', -1, 64)
+	}
+
+	payload := WebhookPayload{
+		Status:   status,
+		Alerts:   []WebhookAlert{alert},
+		Version:  "1",
+		GroupKey: msg.chain,
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		l(slog.LevelWarn, "⚠️ Could not marshal webhook payload!", err)
+		return err
+	}
+
+	req, err := http.NewRequest("POST", msg.alertConfig.Webhook.URL, bytes.NewBuffer(data))
+	if err != nil {
+		l(slog.LevelWarn, "⚠️ Could not create webhook request!", err)
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		l(slog.LevelWarn, "⚠️ Could not send webhook!", err)
+		return err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		l(slog.LevelWarn, "⚠️ Webhook returned non-success status:", resp.StatusCode)
+		return fmt.Errorf("webhook returned status %d for %s", resp.StatusCode, msg.chain)
+	}
+
+	return nil
+}
+
+// sendReport posts the report as JSON, receivers can tell it from alerts by its type, which is digest.
+func (webhookNotifier) sendReport(report *DigestReport, cfg *AlertConfig) error {
+	return postJSON(cfg.Webhook.URL, report)
+}
@@ -0,0 +1,94 @@
+package tenderduty
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Notifier is an alert destination. Adding a new destination only requires a type implementing this interface that
+// registers itself with registerNotifier, and a config section in AlertConfig which it reads from.
+type Notifier interface {
+	// Name uniquely identifies the destination, it is also the key used for the sent-state in the saved state file.
+	Name() string
+	// Enabled reports whether the destination is switched on in an alert config. Both the default and the chain
+	// specific configs must enable a destination before it is used.
+	Enabled(cfg *AlertConfig) bool
+	// SeverityThreshold is the minimum severity the destination is interested in.
+	SeverityThreshold(cfg *AlertConfig) string
+	// Send delivers the alert. It is only called once shouldNotify has approved the message.
+	Send(msg *alertMsg) error
+}
+
+var (
+	notifiersMux sync.RWMutex
+	notifiers    = make(map[string]Notifier)
+)
+
+// registerNotifier adds a destination to the registry, it is intended to be called from an init() function.
+func registerNotifier(n Notifier) {
+	notifiersMux.Lock()
+	defer notifiersMux.Unlock()
+	if _, ok := notifiers[n.Name()]; ok {
+		panic(fmt.Sprintf("notifier %s registered twice", n.Name()))
+	}
+	notifiers[n.Name()] = n
+}
+
+// getNotifier returns a registered destination by name.
+func getNotifier(name string) (Notifier, bool) {
+	notifiersMux.RLock()
+	defer notifiersMux.RUnlock()
+	n, ok := notifiers[name]
+	return n, ok
+}
+
+// allNotifiers returns every registered destination sorted by name, so the delivery order is stable.
+func allNotifiers() []Notifier {
+	notifiersMux.RLock()
+	defer notifiersMux.RUnlock()
+	result := make([]Notifier, 0, len(notifiers))
+	for _, n := range notifiers {
+		result = append(result, n)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name() < result[j].Name()
+	})
+	return result
+}
+
+// enabledNotifiers returns the destinations enabled in both the default and the chain alert configs.
+func enabledNotifiers(defaults, chain *AlertConfig) []Notifier {
+	result := make([]Notifier, 0)
+	for _, n := range allNotifiers() {
+		if n.Enabled(defaults) && n.Enabled(chain) {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// notify fans an alert out to each of its destinations.
+func notify(msg *alertMsg) {
+	for _, n := range msg.notifiers {
+		// failures are logged, and retried, by the outbox
+		_ = deliver(msg, n)
+	}
+}
+
+// deliver sends an alert to a single destination if shouldNotify allows it. The sent-state is only recorded once
+// the destination accepted the notification, failed deliveries are retried from the outbox.
+func deliver(msg *alertMsg, n Notifier) error {
+	if !shouldNotify(msg, n) {
+		return nil
+	}
+	e := outbox.reserve(msg, n)
+	if e == nil {
+		return nil
+	}
+	if groups.add(e) {
+		// sent with the other alerts of its group once the grouping window closes
+		return nil
+	}
+	return outbox.attempt(e)
+}
@@ -0,0 +1,415 @@
+package tenderduty
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	mrand "math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OutboxConfig controls how failed notifications are retried.
+type OutboxConfig struct {
+	// MaxAttempts is how many times a notification is tried before it is moved to the dead letters, 10 by default
+	MaxAttempts int `yaml:"max_attempts"`
+	// InitialBackoff is the wait before the first retry, it doubles with each attempt. 10s by default
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	// MaxBackoff caps the wait between retries, 30m by default
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+}
+
+const maxDeadLetters = 100
+
+// outboxSettings returns the retry settings with defaults for anything that is not configured.
+func outboxSettings() OutboxConfig {
+	o := OutboxConfig{}
+	if td != nil {
+		o = td.Outbox
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = 10
+	}
+	if o.InitialBackoff <= 0 {
+		o.InitialBackoff = 10 * time.Second
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = 30 * time.Minute
+	}
+	return o
+}
+
+// backoff is the wait before the next attempt, exponential with jitter so that retries to a destination that was
+// down don't all arrive at once when it recovers.
+func (o OutboxConfig) backoff(attempts int) time.Duration {
+	d := o.InitialBackoff
+	for i := 1; i < attempts && d < o.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > o.MaxBackoff {
+		d = o.MaxBackoff
+	}
+	half := int64(d / 2)
+	return time.Duration(half + mrand.Int63n(half+1)) // #nosec G404 -- jitter does not need a secure source
+}
+
+// outboxEntry is a notification to a single destination that has not been delivered yet. It holds enough of the
+// alert to rebuild it after a restart.
+type outboxEntry struct {
+	ID          string            `json:"id"`
+	Destination string            `json:"destination"`
+	ConfigName  string            `json:"config_name"`
+	AlertID     string            `json:"alert_id"`
+	Message     string            `json:"message"`
+	Severity    string            `json:"severity"`
+	Resolved    bool              `json:"resolved"`
+	Kind        alertKind         `json:"kind,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Value       float64           `json:"value,omitempty"`
+	Threshold   float64           `json:"threshold,omitempty"`
+	EventID     string            `json:"event_id,omitempty"`
+	Attempts    int               `json:"attempts"`
+	Created     time.Time         `json:"created"`
+	NextAttempt time.Time         `json:"next_attempt"`
+	LastError   string            `json:"last_error,omitempty"`
+
+	msg      *alertMsg
+	dest     Notifier
+	inFlight bool
+	// followUp is sent once this entry is delivered, it is used when an alert resolves, or fires again, while a
+	// delivery for it is in progress. If this entry fails the follow-up is dropped too.
+	followUp *alertMsg
+}
+
+func outboxKey(destination, alertID string) string {
+	return destination + "/" + alertID
+}
+
+type outboxStore struct {
+	mux         sync.Mutex
+	pending     map[string]*outboxEntry
+	deadLetters []*outboxEntry
+	wake        chan struct{}
+}
+
+func newOutboxStore() *outboxStore {
+	return &outboxStore{
+		pending:     make(map[string]*outboxEntry),
+		deadLetters: make([]*outboxEntry, 0),
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// outbox holds the notifications that are being delivered or are waiting to be retried.
+var outbox = newOutboxStore()
+
+// outboxState is how the outbox is saved in the state file.
+type outboxState struct {
+	Pending     []*outboxEntry `json:"pending"`
+	DeadLetters []*outboxEntry `json:"dead_letters"`
+}
+
+// reserve adds a delivery to the outbox, it returns nil if one for the same alert and destination already exists.
+func (o *outboxStore) reserve(msg *alertMsg, n Notifier) *outboxEntry {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	key := outboxKey(n.Name(), msg.uniqueId)
+	if o.pending[key] != nil {
+		return nil
+	}
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	e := &outboxEntry{
+		ID:          hex.EncodeToString(b),
+		Destination: n.Name(),
+		ConfigName:  msg.configName,
+		AlertID:     msg.uniqueId,
+		Message:     msg.message,
+		Severity:    msg.severity,
+		Resolved:    msg.resolved,
+		Kind:        msg.kind,
+		Labels:      msg.labels,
+		Value:       msg.value,
+		Threshold:   msg.threshold,
+		EventID:     msg.eventID,
+		Created:     time.Now(),
+		NextAttempt: time.Now(),
+		msg:         msg,
+		dest:        n,
+		inFlight:    true,
+	}
+	o.pending[key] = e
+	return e
+}
+
+// supersede is called from shouldNotify when a pending delivery exists for the alert and destination. An alert that
+// resolves before its notification was delivered is dropped, nobody saw it so there is nothing to resolve. The same
+// applies to a resolution that has not gone out when the alert fires again. It reports whether the new message
+// should be skipped, which it always is. The caller must hold notifyMux.
+func (o *outboxStore) supersede(msg *alertMsg, service string) bool {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	key := outboxKey(service, msg.uniqueId)
+	e := o.pending[key]
+	if e == nil || e.Resolved == msg.resolved {
+		return e != nil
+	}
+	if e.inFlight {
+		e.followUp = msg
+		return true
+	}
+	l(slog.LevelInfo, fmt.Sprintf("📭 dropping undelivered %s notification on %s (%s) for %s", e.state(), msg.chain, msg.message, service))
+	delete(o.pending, key)
+	return true
+}
+
+func (e *outboxEntry) state() string {
+	if e.Resolved {
+		return "resolved"
+	}
+	return "alert"
+}
+
+// attempt tries to deliver an entry once, on failure it is scheduled for a retry or moved to the dead letters.
+func (o *outboxStore) attempt(e *outboxEntry) error {
+	return o.finish(e, e.dest.Send(e.msg.templated(e.Destination)))
+}
+
+// finish records the result of delivering an entry, on failure it is scheduled for a retry or moved to the dead
+// letters.
+func (o *outboxStore) finish(e *outboxEntry, err error) error {
+	history.delivered(e.EventID, e.Destination, err)
+
+	if err == nil {
+		alarms.recordSent(e.msg, e.dest)
+		o.mux.Lock()
+		delete(o.pending, outboxKey(e.Destination, e.AlertID))
+		followUp := e.followUp
+		o.mux.Unlock()
+		if e.Attempts > 0 {
+			l(slog.LevelInfo, fmt.Sprintf("📬 delivered %s to %s after %d retries", e.AlertID, e.Destination, e.Attempts))
+		}
+		if followUp != nil {
+			return deliver(followUp, e.dest)
+		}
+		return nil
+	}
+
+	settings := outboxSettings()
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	e.inFlight = false
+	e.Attempts += 1
+	e.LastError = err.Error()
+	if e.followUp != nil {
+		// the alert changed state while we were trying, the destination never saw this so drop both
+		l(slog.LevelInfo, fmt.Sprintf("📭 dropping undelivered %s notification for %s to %s", e.state(), e.AlertID, e.Destination))
+		delete(o.pending, outboxKey(e.Destination, e.AlertID))
+		return err
+	}
+	if e.Attempts >= settings.MaxAttempts {
+		l(slog.LevelError, fmt.Sprintf("💀 giving up on %s notification for %s to %s after %d attempts: %s", e.state(), e.AlertID, e.Destination, e.Attempts, err))
+		delete(o.pending, outboxKey(e.Destination, e.AlertID))
+		o.deadLetters = append(o.deadLetters, e)
+		if len(o.deadLetters) > maxDeadLetters {
+			o.deadLetters = o.deadLetters[len(o.deadLetters)-maxDeadLetters:]
+		}
+		return err
+	}
+	e.NextAttempt = time.Now().Add(settings.backoff(e.Attempts))
+	l(slog.LevelWarn, fmt.Sprintf("📮 could not notify %s for %s, retry %d at %s: %s", e.Destination, e.AlertID, e.Attempts, e.NextAttempt.Format(time.TimeOnly), err))
+	o.signal()
+	return err
+}
+
+func (o *outboxStore) signal() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run retries deliveries as they come due until the context is cancelled.
+func (o *outboxStore) run(ctx context.Context) {
+	timer := time.NewTimer(time.Second)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-o.wake:
+		case <-timer.C:
+		}
+		next := o.retryDue()
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(next)
+	}
+}
+
+// retryDue starts the retries that are due, and returns how long until the next one.
+func (o *outboxStore) retryDue() time.Duration {
+	now := time.Now()
+	next := time.Minute
+	due := make([]*outboxEntry, 0)
+	o.mux.Lock()
+	for _, e := range o.pending {
+		if e.inFlight {
+			continue
+		}
+		if !e.NextAttempt.After(now) {
+			e.inFlight = true
+			due = append(due, e)
+		} else if wait := e.NextAttempt.Sub(now); wait < next {
+			next = wait
+		}
+	}
+	o.mux.Unlock()
+	for _, e := range due {
+		go func(e *outboxEntry) {
+			_ = o.attempt(e)
+		}(e)
+	}
+	return next
+}
+
+// retryDeadLetter moves a dead letter back to the outbox, it is retried right away.
+func (o *outboxStore) retryDeadLetter(id string) bool {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	for i, e := range o.deadLetters {
+		if e.ID != id {
+			continue
+		}
+		o.deadLetters = append(o.deadLetters[:i], o.deadLetters[i+1:]...)
+		key := outboxKey(e.Destination, e.AlertID)
+		if o.pending[key] == nil {
+			e.Attempts = 0
+			e.NextAttempt = time.Now()
+			o.pending[key] = e
+			o.signal()
+		}
+		return true
+	}
+	return false
+}
+
+// snapshot copies the outbox for saving or showing on the dashboard, oldest first.
+func (o *outboxStore) snapshot() *outboxState {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	state := &outboxState{
+		Pending:     make([]*outboxEntry, 0, len(o.pending)),
+		DeadLetters: make([]*outboxEntry, 0, len(o.deadLetters)),
+	}
+	for _, e := range o.pending {
+		c := *e
+		state.Pending = append(state.Pending, &c)
+	}
+	sort.Slice(state.Pending, func(i, j int) bool { return state.Pending[i].Created.Before(state.Pending[j].Created) })
+	for _, e := range o.deadLetters {
+		c := *e
+		state.DeadLetters = append(state.DeadLetters, &c)
+	}
+	return state
+}
+
+// restore loads the outbox from the state file. Entries for chains or destinations that are no longer configured
+// are dropped.
+func (o *outboxStore) restore(c *Config, state *outboxState) {
+	if state == nil {
+		return
+	}
+	rebuild := func(e *outboxEntry) bool {
+		cc := c.Chains[e.ConfigName]
+		if cc == nil {
+			return false
+		}
+		dest, ok := destinationFor(e.Destination, &cc.Alerts)
+		if !ok {
+			return false
+		}
+		e.dest = dest
+		e.msg = c.newAlertMsg(e.ConfigName, cc, e.Message, e.Severity, e.Resolved, e.AlertID)
+		e.msg.alertDetails = alertDetails{kind: e.Kind, labels: e.Labels, value: e.Value, threshold: e.Threshold}
+		e.msg.eventID = e.EventID
+		if e.Kind == "" {
+			e.msg.kind = kindOf(e.AlertID)
+		}
+		return true
+	}
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	for _, e := range state.Pending {
+		if !rebuild(e) {
+			l(slog.LevelWarn, fmt.Sprintf("🗑 not restoring undelivered notification for %s to %s", e.AlertID, e.Destination))
+			continue
+		}
+		e.inFlight = false
+		o.pending[outboxKey(e.Destination, e.AlertID)] = e
+	}
+	for _, e := range state.DeadLetters {
+		if rebuild(e) {
+			o.deadLetters = append(o.deadLetters, e)
+		}
+	}
+	if len(o.pending) > 0 {
+		l(slog.LevelInfo, fmt.Sprintf("📮 restored %d undelivered notifications", len(o.pending)))
+	}
+}
+
+// destinationFor finds the Notifier for a name in the sent-state, including escalation stages.
+func destinationFor(name string, chainAlerts *AlertConfig) (Notifier, bool) {
+	base, stage, escalated := strings.Cut(name, escalationSeparator)
+	if !escalated {
+		return getNotifier(base)
+	}
+	i, err := strconv.Atoi(stage)
+	if err != nil || i < 1 || i > len(chainAlerts.Escalation.Stages) {
+		return nil, false
+	}
+	for _, n := range chainAlerts.Escalation.stageNotifiers(i-1, chainAlerts) {
+		if n.Name() == name {
+			return n, true
+		}
+	}
+	return nil, false
+}
+
+// outboxHandler implements the dashboard's /api/outbox endpoint:
+//
+//	GET    lists the pending notifications and the dead letters
+//	POST   retries the dead letter given in the id query parameter
+//
+// Retrying requires the dashboard_api_token, listing is open unless logs are hidden on the dashboard.
+func outboxHandler(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet || td.HideLogs {
+		if !apiAuthorized(request) {
+			http.Error(writer, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+	switch request.Method {
+	case http.MethodGet:
+		writeJSON(writer, http.StatusOK, outbox.snapshot())
+	case http.MethodPost:
+		if !outbox.retryDeadLetter(request.URL.Query().Get("id")) {
+			http.Error(writer, "dead letter not found", http.StatusNotFound)
+			return
+		}
+		writer.WriteHeader(http.StatusAccepted)
+	default:
+		writer.Header().Set("Allow", "GET, POST")
+		http.Error(writer, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,262 @@
+package tenderduty
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyNotifier fails until it is told to accept deliveries.
+type flakyNotifier struct {
+	discordNotifier
+	mux  *sync.Mutex
+	fail *bool
+	sent *[]*alertMsg
+}
+
+func newFlakyNotifier(fail bool) flakyNotifier {
+	return flakyNotifier{mux: &sync.Mutex{}, fail: &fail, sent: &[]*alertMsg{}}
+}
+
+func (f flakyNotifier) Send(msg *alertMsg) error {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	if *f.fail {
+		return errors.New("destination is down")
+	}
+	*f.sent = append(*f.sent, msg)
+	return nil
+}
+
+func (f flakyNotifier) setFail(fail bool) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	*f.fail = fail
+}
+
+func setupOutboxTest(t *testing.T) {
+	originalTd, originalOutbox := td, outbox
+	td = createTestConfig()
+	newTestAlarmCache(t)
+	outbox = newOutboxStore()
+	t.Cleanup(func() { td, outbox = originalTd, originalOutbox })
+}
+
+func testOutboxMsg(resolved bool) *alertMsg {
+	return &alertMsg{
+		configName:  "test-chain",
+		chain:       "test-chain (test-chain-1)",
+		uniqueId:    "ValidatorInactive_testval123",
+		message:     "jailed",
+		severity:    "critical",
+		resolved:    resolved,
+		alertConfig: &AlertConfig{},
+	}
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	o := OutboxConfig{InitialBackoff: 10 * time.Second, MaxBackoff: time.Minute}
+	for attempts, max := range map[int]time.Duration{1: 10 * time.Second, 2: 20 * time.Second, 3: 40 * time.Second, 8: time.Minute} {
+		for i := 0; i < 20; i++ {
+			if d := o.backoff(attempts); d < max/2 || d > max {
+				t.Errorf("backoff(%d) = %s, want between %s and %s", attempts, d, max/2, max)
+			}
+		}
+	}
+}
+
+func TestOutboxSettingsDefaults(t *testing.T) {
+	originalTd := td
+	td = &Config{Outbox: OutboxConfig{MaxAttempts: 3}}
+	defer func() { td = originalTd }()
+
+	o := outboxSettings()
+	if o.MaxAttempts != 3 || o.InitialBackoff != 10*time.Second || o.MaxBackoff != 30*time.Minute {
+		t.Errorf("unexpected settings %+v", o)
+	}
+}
+
+func TestDeliverRecordsSentOnlyAfterSuccess(t *testing.T) {
+	setupOutboxTest(t)
+	dest := newFlakyNotifier(true)
+	msg := testOutboxMsg(false)
+
+	if err := deliver(msg, dest); err == nil {
+		t.Fatal("expected the delivery to fail")
+	}
+	if _, ok := alarms.sentFor("discord")[msg.uniqueId]; ok {
+		t.Error("failed delivery should not be recorded as sent")
+	}
+	state := outbox.snapshot()
+	if len(state.Pending) != 1 || state.Pending[0].Attempts != 1 || state.Pending[0].LastError == "" {
+		t.Fatalf("failed delivery should wait for a retry, got %+v", state.Pending)
+	}
+	if !state.Pending[0].NextAttempt.After(time.Now()) {
+		t.Error("retry should be scheduled after a backoff")
+	}
+
+	// the alert is raised again while waiting, it is not sent twice
+	if err := deliver(msg, dest); err != nil {
+		t.Errorf("pending delivery should not be attempted again, got %v", err)
+	}
+
+	// the retry succeeds
+	dest.setFail(false)
+	e := outbox.pending[outboxKey("discord", msg.uniqueId)]
+	if outbox.retryDue() > time.Minute || e.inFlight {
+		t.Error("retry should not start before the backoff")
+	}
+	e.inFlight = true
+	if err := outbox.attempt(e); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(outbox.snapshot().Pending) != 0 {
+		t.Fatal("delivered notification should leave the outbox")
+	}
+	if _, ok := alarms.sentFor("discord")[msg.uniqueId]; !ok {
+		t.Error("delivered notification should be recorded as sent")
+	}
+	if len(*dest.sent) != 1 {
+		t.Errorf("expected 1 delivery, got %d", len(*dest.sent))
+	}
+}
+
+func TestOutboxDeadLetter(t *testing.T) {
+	setupOutboxTest(t)
+	td.Outbox.MaxAttempts = 2
+	dest := newFlakyNotifier(true)
+	msg := testOutboxMsg(false)
+
+	_ = deliver(msg, dest)
+	e := outbox.pending[outboxKey("discord", msg.uniqueId)]
+	e.inFlight = true
+	_ = outbox.attempt(e)
+
+	state := outbox.snapshot()
+	if len(state.Pending) != 0 || len(state.DeadLetters) != 1 || state.DeadLetters[0].Attempts != 2 {
+		t.Fatalf("notification should be a dead letter after 2 attempts, got %+v", state)
+	}
+
+	if outbox.retryDeadLetter("unknown") {
+		t.Error("unknown dead letter should not be retried")
+	}
+	if !outbox.retryDeadLetter(state.DeadLetters[0].ID) {
+		t.Fatal("dead letter should be retried")
+	}
+	state = outbox.snapshot()
+	if len(state.Pending) != 1 || len(state.DeadLetters) != 0 || state.Pending[0].Attempts != 0 {
+		t.Errorf("retried dead letter should be pending again, got %+v", state)
+	}
+}
+
+func TestOutboxResolvedBeforeDelivery(t *testing.T) {
+	setupOutboxTest(t)
+	dest := newFlakyNotifier(true)
+
+	_ = deliver(testOutboxMsg(false), dest)
+	if err := deliver(testOutboxMsg(true), dest); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if len(outbox.snapshot().Pending) != 0 {
+		t.Error("alert that resolved before it was delivered should be dropped")
+	}
+	if len(*dest.sent) != 0 {
+		t.Error("nothing should have been delivered")
+	}
+}
+
+func TestOutboxFollowUpAfterInFlight(t *testing.T) {
+	setupOutboxTest(t)
+	dest := newFlakyNotifier(false)
+	msg := testOutboxMsg(false)
+
+	// the alert resolves while its delivery is in progress
+	e := outbox.reserve(msg, dest)
+	if deliver(testOutboxMsg(true), dest) != nil || len(*dest.sent) != 0 {
+		t.Fatal("resolution should wait for the alert to be delivered")
+	}
+	if err := outbox.attempt(e); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(*dest.sent) != 2 || !(*dest.sent)[1].resolved {
+		t.Fatalf("expected the alert and then its resolution, got %d deliveries", len(*dest.sent))
+	}
+	if _, ok := alarms.sentFor("discord")[msg.uniqueId]; ok {
+		t.Error("resolution should clear the sent state")
+	}
+}
+
+func TestOutboxSnapshotRestore(t *testing.T) {
+	setupOutboxTest(t)
+	dest := newFlakyNotifier(true)
+	_ = deliver(testOutboxMsg(false), dest)
+	gone := testOutboxMsg(false)
+	gone.configName = "removed-chain"
+	_ = deliver(gone, discordNotifier{})
+
+	b, err := json.Marshal(outbox.snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := &outboxState{}
+	if err = json.Unmarshal(b, saved); err != nil {
+		t.Fatal(err)
+	}
+
+	outbox = newOutboxStore()
+	outbox.restore(td, saved)
+	state := outbox.snapshot()
+	if len(state.Pending) != 1 {
+		t.Fatalf("expected the notification for the configured chain to be restored, got %+v", state.Pending)
+	}
+	e := outbox.pending[outboxKey("discord", "ValidatorInactive_testval123")]
+	if e.inFlight || e.dest == nil || e.msg == nil || e.msg.message != "jailed" || e.msg.chainId != "test-chain-1" {
+		t.Errorf("restored entry should be ready to retry, got %+v", e)
+	}
+}
+
+func TestDestinationFor(t *testing.T) {
+	chainAlerts := &AlertConfig{Escalation: testEscalationConfig()}
+	if n, ok := destinationFor("discord", chainAlerts); !ok || n.Name() != "discord" {
+		t.Error("expected the discord destination")
+	}
+	if n, ok := destinationFor("pagerduty@escalation-2", chainAlerts); !ok || n.Name() != "pagerduty@escalation-2" {
+		t.Error("expected the escalation stage destination")
+	}
+	if _, ok := destinationFor("pagerduty@escalation-5", chainAlerts); ok {
+		t.Error("unknown escalation stage should not be found")
+	}
+}
+
+func TestOutboxHandler(t *testing.T) {
+	setupOutboxTest(t)
+	td.DashboardAPIToken = "secret"
+	td.Outbox.MaxAttempts = 1
+	_ = deliver(testOutboxMsg(false), newFlakyNotifier(true))
+	id := outbox.snapshot().DeadLetters[0].ID
+
+	rec := httptest.NewRecorder()
+	outboxHandler(rec, httptest.NewRequest(http.MethodGet, "/api/outbox", nil))
+	state := &outboxState{}
+	if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), state) != nil || len(state.DeadLetters) != 1 {
+		t.Fatalf("unexpected response %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	outboxHandler(rec, httptest.NewRequest(http.MethodPost, "/api/outbox?id="+id, nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("retry without a token should be unauthorized, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/outbox?id="+id, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	outboxHandler(rec, req)
+	if rec.Code != http.StatusAccepted || len(outbox.snapshot().Pending) != 1 {
+		t.Errorf("retry should be accepted, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,118 @@
+package tenderduty
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// pendingKinds are the alert types that are evaluated on every check, so that a `for` duration can tell if their
+// condition held the whole time. The other types fire on a single change and cannot be pending.
+var pendingKinds = []alertKind{
+	kindConsecutiveBlocksMissed,
+	kindPercentageBlocksMissed,
+	kindConsecutiveEmptyBlocks,
+	kindPercentageEmptyBlocks,
+	kindUnclaimedRewards,
+}
+
+// pendingAlert is an alert whose condition has not held for its `for` duration yet, or an active alert that has not
+// been clear for its `resolve_for` duration yet.
+type pendingAlert struct {
+	message  string
+	since    time.Time
+	resolved bool
+}
+
+// waitFor returns how long the condition of an alert must hold before it fires, or before it resolves.
+func waitFor(ac *AlertConfig, kind alertKind, resolved bool) time.Duration {
+	if resolved {
+		return ac.ResolveFor[string(kind)]
+	}
+	return ac.For[string(kind)]
+}
+
+// held reports if an alert that fires or resolves is held back as pending. The first time its condition is met the
+// pending state starts, and it is released once the condition held for wait. When the condition goes back before then
+// the pending state is dropped, and the alert is held too since nothing changed. The caller must hold notifyMux.
+func (a *alarmCache) held(msg *alertMsg, active bool, wait time.Duration) bool {
+	p := a.pending[msg.configName][msg.uniqueId]
+	if msg.resolved != active {
+		// raised again while active, or resolved while not active
+		if p == nil {
+			return false
+		}
+		delete(a.pending[msg.configName], msg.uniqueId)
+		l(slog.LevelInfo, fmt.Sprintf("⏳ Cancelled    pending alarm on %s (%s), the condition did not hold for %s", msg.chain, p.message, time.Since(p.since).Round(time.Second)))
+		return true
+	}
+	if wait <= 0 {
+		delete(a.pending[msg.configName], msg.uniqueId)
+		return false
+	}
+	if p == nil || p.resolved != msg.resolved {
+		if a.pending == nil {
+			a.pending = make(map[string]map[string]*pendingAlert)
+		}
+		if a.pending[msg.configName] == nil {
+			a.pending[msg.configName] = make(map[string]*pendingAlert)
+		}
+		a.pending[msg.configName][msg.uniqueId] = &pendingAlert{message: msg.message, since: time.Now(), resolved: msg.resolved}
+		l(slog.LevelInfo, fmt.Sprintf("⏳ Pending      alarm on %s (%s) for %s", msg.chain, msg.message, wait))
+		return true
+	}
+	if time.Since(p.since) < wait {
+		p.message = msg.message
+		return true
+	}
+	delete(a.pending[msg.configName], msg.uniqueId)
+	return false
+}
+
+// isPending reports if an alert is waiting to fire or resolve.
+func (a *alarmCache) isPending(chain, alertID string) bool {
+	a.notifyMux.RLock()
+	defer a.notifyMux.RUnlock()
+	return a.pending[chain][alertID] != nil
+}
+
+// pendingCount returns the number of alerts on a chain that are waiting to fire, alerts waiting to resolve are
+// still counted as active.
+func (a *alarmCache) pendingCount(chain string) int {
+	a.notifyMux.RLock()
+	defer a.notifyMux.RUnlock()
+	n := 0
+	for _, p := range a.pending[chain] {
+		if !p.resolved {
+			n += 1
+		}
+	}
+	return n
+}
+
+// validatePending checks the `for` and `resolve_for` durations of an alerts section.
+func validatePending(name string, ac *AlertConfig) (fatal bool, problems []string) {
+	settings := []struct {
+		name      string
+		durations map[string]time.Duration
+	}{{"for", ac.For}, {"resolve_for", ac.ResolveFor}}
+	for _, setting := range settings {
+		for alertType, d := range setting.durations {
+			// a duration for any other kind would never be used, that is a mistake in the config
+			switch {
+			case !slices.Contains(alertKinds, alertKind(alertType)):
+				fatal = true
+				problems = append(problems, fmt.Sprintf("error: %s has a %s duration for an unknown alert type %s", name, setting.name, alertType))
+			case !slices.Contains(pendingKinds, alertKind(alertType)):
+				fatal = true
+				problems = append(problems, fmt.Sprintf("error: %s has a %s duration for %s, which fires on a single change and cannot be pending", name, setting.name, alertType))
+			}
+			if d < 0 {
+				fatal = true
+				problems = append(problems, fmt.Sprintf("error: %s has a negative %s duration for %s", name, setting.name, alertType))
+			}
+		}
+	}
+	return
+}
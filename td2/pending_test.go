@@ -0,0 +1,103 @@
+package tenderduty
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// backdatePending makes a pending alert look like it has been waiting for d.
+func backdatePending(id string, d time.Duration) {
+	alarms.notifyMux.Lock()
+	defer alarms.notifyMux.Unlock()
+	alarms.pending["test-chain"][id].since = time.Now().Add(-d)
+}
+
+func TestPendingFor(t *testing.T) {
+	setupOutboxTest(t)
+	td.Chains["test-chain"].Alerts.For = map[string]time.Duration{"PercentageBlocksMissed": time.Minute}
+	id := "PercentageBlocksMissed_testval123"
+	details := alertDetails{kind: kindPercentageBlocksMissed}
+
+	td.alert("test-chain", "missed 10%", "warning", false, &id, details)
+	if msgs := drainAlerts(); len(msgs) != 0 || !alarms.isPending("test-chain", id) || alarms.exist("test-chain", id) {
+		t.Fatal("the alert should be pending")
+	}
+	if alarms.pendingCount("test-chain") != 1 || !strings.Contains(getAlarms("test-chain"), "⏳ missed 10% (pending firing for") {
+		t.Errorf("the pending alert should be shown, got %q", getAlarms("test-chain"))
+	}
+
+	// the condition cleared before the duration, nothing is sent
+	td.alert("test-chain", "missed 10%", "warning", true, &id, details)
+	if msgs := drainAlerts(); len(msgs) != 0 || alarms.isPending("test-chain", id) {
+		t.Fatal("the pending alert should be dropped")
+	}
+
+	td.alert("test-chain", "missed 10%", "warning", false, &id, details)
+	backdatePending(id, 30*time.Second)
+	td.alert("test-chain", "missed 12%", "warning", false, &id, details)
+	if msgs := drainAlerts(); len(msgs) != 0 {
+		t.Fatal("the alert has not been pending for long enough")
+	}
+	backdatePending(id, 2*time.Minute)
+	td.alert("test-chain", "missed 12%", "warning", false, &id, details)
+	msgs := drainAlerts()
+	if len(msgs) != 1 || msgs[0].resolved || !alarms.exist("test-chain", id) || alarms.isPending("test-chain", id) {
+		t.Fatalf("the alert should fire once the condition held, got %+v", msgs)
+	}
+
+	// without a resolve_for the resolution is sent right away
+	td.alert("test-chain", "missed 12%", "warning", true, &id, details)
+	if msgs = drainAlerts(); len(msgs) != 1 || !msgs[0].resolved {
+		t.Fatalf("expected the resolution, got %+v", msgs)
+	}
+}
+
+func TestPendingResolveFor(t *testing.T) {
+	setupOutboxTest(t)
+	td.Chains["test-chain"].Alerts.ResolveFor = map[string]time.Duration{"ConsecutiveEmptyBlocks": time.Minute}
+	id := "ConsecutiveEmptyBlocks_testval123"
+	details := alertDetails{kind: kindConsecutiveEmptyBlocks}
+
+	td.alert("test-chain", "empty blocks", "warning", false, &id, details)
+	if msgs := drainAlerts(); len(msgs) != 1 {
+		t.Fatal("an alert without a for duration fires right away")
+	}
+
+	td.alert("test-chain", "empty blocks", "warning", true, &id, details)
+	if msgs := drainAlerts(); len(msgs) != 0 || !alarms.exist("test-chain", id) || alarms.pendingCount("test-chain") != 0 {
+		t.Fatal("the resolution should be pending, and the alert still active")
+	}
+	// the condition came back, the alert is not raised again
+	td.alert("test-chain", "empty blocks", "warning", false, &id, details)
+	if msgs := drainAlerts(); len(msgs) != 0 || alarms.isPending("test-chain", id) {
+		t.Fatal("the pending resolution should be dropped")
+	}
+
+	td.alert("test-chain", "empty blocks", "warning", true, &id, details)
+	backdatePending(id, 2*time.Minute)
+	td.alert("test-chain", "empty blocks", "warning", true, &id, details)
+	if msgs := drainAlerts(); len(msgs) != 1 || !msgs[0].resolved || alarms.exist("test-chain", id) {
+		t.Fatalf("expected the resolution once the condition was clear, got %+v", msgs)
+	}
+}
+
+func TestValidatePending(t *testing.T) {
+	ac := &AlertConfig{
+		For:        map[string]time.Duration{"ValidatorInactive": time.Minute},
+		ResolveFor: map[string]time.Duration{"PercentageEmptyBlocks": -time.Minute},
+	}
+	fatal, problems := validatePending("osmosis", ac)
+	expected := []string{
+		"error: osmosis has a for duration for ValidatorInactive, which fires on a single change and cannot be pending",
+		"error: osmosis has a negative resolve_for duration for PercentageEmptyBlocks",
+	}
+	if !fatal || strings.Join(problems, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected problems:\n%s", strings.Join(problems, "\n"))
+	}
+
+	ac = &AlertConfig{For: map[string]time.Duration{"PercentageBlocksMissed": 5 * time.Minute, "Unknown": time.Minute}}
+	if fatal, problems = validatePending("osmosis", ac); !fatal || len(problems) != 1 {
+		t.Errorf("an unknown alert type should be rejected, got %v", problems)
+	}
+}
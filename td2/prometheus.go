@@ -27,6 +27,7 @@ const (
 	metricConsecutive
 	metricEmptyBlocks
 	metricConsecutiveEmpty
+	metricMissedProposals
 	metricWindowMissed
 	metricWindowSize
 	metricLastBlockSeconds
@@ -36,6 +37,7 @@ const (
 	metricUnealthyNodes
 	metricNodeLagSeconds
 	metricNodeDownSeconds
+	metricNodeEventLatency
 
 	metricUnvotedProposals
 )
@@ -59,7 +61,7 @@ func (m metrics) setStat(update *promUpdate) {
 	}
 	promMux.RLock()
 	defer promMux.RUnlock()
-	if update.metric == metricNodeLagSeconds || update.metric == metricNodeDownSeconds {
+	if update.metric == metricNodeLagSeconds || update.metric == metricNodeDownSeconds || update.metric == metricNodeEventLatency {
 		lbls["endpoint"] = update.endpoint
 	}
 	m[update.metric].With(lbls).Set(update.counter)
@@ -103,6 +105,10 @@ func prometheusExporter(ctx context.Context, updates chan *promUpdate) {
 		Name: "tenderduty_consecutive_empty_blocks",
 		Help: "the current count of consecutively proposed empty blocks",
 	}, chainLabels)
+	missedProposals := promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "tenderduty_missed_proposals",
+		Help: "count of heights the validator was the round 0 proposer for but another validator's block was committed since tenderduty was started",
+	}, chainLabels)
 	windowSize := promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "tenderduty_missed_block_window",
 		Help: "the missed block aka slashing window",
@@ -143,6 +149,10 @@ func prometheusExporter(ctx context.Context, updates chan *promUpdate) {
 		Name: "tenderduty_endpoint_down_seconds",
 		Help: "how many seconds a node has been marked as unhealthy",
 	}, hostLabels)
+	nodeEventLatency := promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "tenderduty_endpoint_event_latency_seconds",
+		Help: "how many seconds after the first subscribed node a node delivered its latest block or vote event",
+	}, hostLabels)
 
 	m := metrics{
 		metricSigned:                   signed,
@@ -153,6 +163,7 @@ func prometheusExporter(ctx context.Context, updates chan *promUpdate) {
 		metricConsecutive:              missedConsecutive,
 		metricEmptyBlocks:              emptyBlocks,
 		metricConsecutiveEmpty:         consecutiveEmpty,
+		metricMissedProposals:          missedProposals,
 		metricWindowMissed:             missedWindow,
 		metricWindowSize:               windowSize,
 		metricLastBlockSeconds:         lastBlockSec,
@@ -161,6 +172,7 @@ func prometheusExporter(ctx context.Context, updates chan *promUpdate) {
 		metricUnealthyNodes:            nodesUnhealthy,
 		metricNodeLagSeconds:           nodeLagSec,  // todo
 		metricNodeDownSeconds:          nodeDownSec, // todo
+		metricNodeEventLatency:         nodeEventLatency,
 		metricUnvotedProposals:         unvotedProposals,
 	}
 
@@ -0,0 +1,89 @@
+package tenderduty
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+// voteRounds remembers the highest consensus round seen in the votes for each height. A height that went past round
+// 0 had a proposer that did not get its block committed.
+type voteRounds map[int64]int32
+
+// saw records the round of a vote and reports if it is the highest round seen for its height so far.
+func (vr voteRounds) saw(height int64, round int32) bool {
+	if round <= vr[height] {
+		return false
+	}
+	vr[height] = round
+	for h := range vr {
+		if h <= height-trackedHeights {
+			delete(vr, h)
+		}
+	}
+	return true
+}
+
+// roundZeroProposer returns the address of the validator expected to propose a height at round 0. The priorities
+// returned for a height are the ones after its proposer was picked, so the proposer is found by moving the priorities
+// of the previous height forward by one, the way the consensus does.
+func (cc *ChainConfig) roundZeroProposer(ctx context.Context, height int64) ([]byte, error) {
+	if height <= 1 {
+		return nil, fmt.Errorf("the proposer of %d can't be derived", height)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	previous := height - 1
+	vals := make([]*types.Validator, 0)
+	perPage := 100
+	for page := 1; ; page++ {
+		res, err := cc.client.Validators(ctx, &previous, &page, &perPage)
+		if err != nil {
+			return nil, fmt.Errorf("could not get the validators at %d: %w", previous, err)
+		}
+		vals = append(vals, res.Validators...)
+		if len(res.Validators) == 0 || len(vals) >= res.Total {
+			break
+		}
+	}
+	if len(vals) == 0 {
+		return nil, fmt.Errorf("no validators at %d", previous)
+	}
+	proposer := (&types.ValidatorSet{Validators: vals}).CopyIncrementProposerPriority(1).GetProposer()
+	if proposer == nil {
+		return nil, errors.New("no proposer found")
+	}
+	return proposer.Address, nil
+}
+
+// missedProposal reports if the validator was the round 0 proposer of a height, but another validator's block was
+// committed in a later round. The validator set is only looked up for heights that went past round 0.
+func (cc *ChainConfig) missedProposal(ctx context.Context, height int64, round int32, signState StatusType) bool {
+	if round == 0 || signState >= StatusProposed || !cc.valInfo.Bonded {
+		return false
+	}
+	proposer, err := cc.roundZeroProposer(ctx, height)
+	if err != nil {
+		l(slog.LevelWarn, fmt.Sprintf("🗳️ %-12s could not check the proposer of %d: %s", cc.ChainId, height, err))
+		return false
+	}
+	return bytes.Equal(proposer, cc.valInfo.Conspub)
+}
+
+// countMissedProposal shows a missed proposal on the dashboard's grid, unless the block was missed as well which is
+// more important to see, and counts it. It returns a warning for the dashboard.
+func (cc *ChainConfig) countMissedProposal(height int64, signState StatusType) (warn string) {
+	if signState >= StatusSigned {
+		cc.blocksResults[0] = int(StatusMissedProposal)
+	}
+	cc.statTotalMissedProps += 1
+	cc.statConsecutiveMissedProps += 1
+	warn = fmt.Sprintf("❌ warning      %s missed its proposal for %d on %s", cc.valInfo.Moniker, height, cc.ChainId)
+	l(slog.LevelWarn, warn)
+	return
+}
@@ -0,0 +1,143 @@
+package tenderduty
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// newValidatorsServer answers the validators RPC call with the validator set of the requested height, one validator
+// per page so that paging is exercised.
+func newValidatorsServer(t *testing.T, sets map[int64]*types.ValidatorSet) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := struct {
+			ID     json.RawMessage `json:"id"`
+			Params struct {
+				Height string `json:"height"`
+				Page   string `json:"page"`
+			} `json:"params"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+			return
+		}
+		height, _ := strconv.ParseInt(req.Params.Height, 10, 64)
+		set := sets[height]
+		if set == nil {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"error":{"code":-32603,"message":"no validators at ` + req.Params.Height + `"}}`))
+			return
+		}
+		page, _ := strconv.Atoi(req.Params.Page)
+		result := &ctypes.ResultValidators{BlockHeight: height, Validators: set.Validators[page-1 : page], Count: 1, Total: set.Size()}
+		body, err := tmjson.Marshal(result)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":` + string(body) + `}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestVoteRounds(t *testing.T) {
+	vr := make(voteRounds)
+	if vr.saw(10, 0) {
+		t.Error("round 0 is not a new round")
+	}
+	if !vr.saw(10, 1) || vr.saw(10, 1) || vr.saw(10, 0) {
+		t.Error("only a higher round should be reported")
+	}
+	if !vr.saw(10, 2) {
+		t.Error("round 2 is higher than round 1")
+	}
+	vr.saw(10+trackedHeights, 1)
+	if _, ok := vr[10]; ok {
+		t.Error("old heights should be forgotten")
+	}
+}
+
+func TestMissedProposal(t *testing.T) {
+	setupOutboxTest(t)
+	address := []byte("01234567890123456789")
+	other := []byte("abcdefghijabcdefghij")
+	// the other validator has the highest priority in both sets as they are returned, but the validator has three
+	// times the voting power and is the one picked for 100 once the priorities of 99 are moved forward
+	before := &types.ValidatorSet{Validators: []*types.Validator{
+		{Address: address, VotingPower: 30, ProposerPriority: -5},
+		{Address: other, VotingPower: 10, ProposerPriority: 5},
+	}}
+	after := before.CopyIncrementProposerPriority(1)
+	if after.Validators[1].ProposerPriority <= after.Validators[0].ProposerPriority {
+		t.Fatalf("the set of 100 should not have the proposer first, got %v", after.Validators)
+	}
+	server := newValidatorsServer(t, map[int64]*types.ValidatorSet{99: before, 100: after})
+
+	cc := td.Chains["test-chain"]
+	cc.valInfo = &ValInfo{Moniker: "testval", Bonded: true, Conspub: address}
+	cc.blocksResults = make([]int, showBLocks)
+	var err error
+	if cc.client, err = rpchttp.New(server.URL, "/websocket"); err != nil {
+		t.Fatal(err)
+	}
+
+	proposer, err := cc.roundZeroProposer(context.Background(), 100)
+	if err != nil || string(proposer) != string(address) {
+		t.Fatalf("expected the validator to be the round 0 proposer, got %q: %v", proposer, err)
+	}
+	if _, err = cc.roundZeroProposer(context.Background(), 99); err == nil {
+		t.Error("the proposer can't be found without the validators of the previous height")
+	}
+	if cc.missedProposal(context.Background(), 100, 0, StatusSigned) || cc.missedProposal(context.Background(), 100, 1, StatusProposed) {
+		t.Error("a height decided in round 0, or proposed by the validator, is not a missed proposal")
+	}
+	if !cc.missedProposal(context.Background(), 100, 1, StatusSigned) {
+		t.Fatal("the validator should have missed its proposal")
+	}
+
+	cc.countBlock(100, StatusSigned)
+	cc.countMissedProposal(100, StatusSigned)
+	cc.countBlock(101, Statusmissed)
+	cc.countMissedProposal(101, Statusmissed)
+	if cc.blocksResults[0] != int(Statusmissed) || cc.blocksResults[1] != int(StatusMissedProposal) {
+		t.Errorf("unexpected grid %v", cc.blocksResults[:2])
+	}
+	if cc.statTotalMissedProps != 2 || cc.statConsecutiveMissedProps != 2 || cc.statTotalSigns != 1 {
+		t.Errorf("unexpected counters: %v missed proposals, %v in a row, %v signed", cc.statTotalMissedProps, cc.statConsecutiveMissedProps, cc.statTotalSigns)
+	}
+}
+
+func TestMissedProposalsAlert(t *testing.T) {
+	setupOutboxTest(t)
+	cc := td.Chains["test-chain"]
+	cc.valInfo = &ValInfo{Moniker: "testval"}
+	cc.blocksResults = make([]int, showBLocks)
+	threshold := 2
+	cc.Alerts.MissedProposals = &threshold
+	id := "MissedProposals_testval123"
+
+	cc.statConsecutiveMissedProps = 1
+	if alert, _ := evaluateMissedProposalsAlert(cc); alert {
+		t.Fatal("one missed proposal is below the threshold")
+	}
+	cc.statConsecutiveMissedProps = 2
+	if alert, _ := evaluateMissedProposalsAlert(cc); !alert || !alarms.exist("test-chain", id) {
+		t.Fatal("expected a missed proposals alert")
+	}
+	// proposing a block resets the count and resolves the alert
+	cc.countBlock(200, StatusProposed)
+	if _, resolved := evaluateMissedProposalsAlert(cc); !resolved || alarms.exist("test-chain", id) {
+		t.Fatal("the alert should resolve once the validator proposes")
+	}
+	if msgs := drainAlerts(); len(msgs) != 2 || msgs[0].resolved || !msgs[1].resolved {
+		t.Fatalf("expected the alert and its resolution, got %+v", msgs)
+	}
+}
@@ -0,0 +1,124 @@
+package tenderduty
+
+import (
+	"strings"
+	"time"
+)
+
+// RepeatRule re-sends an alert that is still active to a destination every RepeatInterval, for example to page
+// again about a jailed validator. AlertType is matched as a prefix of the alert ID, and an empty Destination applies
+// to every destination. MaxRepeats caps the number of reminders, zero means no limit.
+type RepeatRule struct {
+	AlertType      string        `yaml:"alert_type"`
+	Destination    string        `yaml:"destination"`
+	RepeatInterval time.Duration `yaml:"repeat_interval"`
+	MaxRepeats     int           `yaml:"max_repeats"`
+}
+
+// repeatRules are the rules from the config file.
+var repeatRules []RepeatRule
+
+// governanceRepeatRule is the built-in reminder for unvoted proposals, it is used unless a configured rule matches.
+func governanceRepeatRule() *RepeatRule {
+	hours := 6
+	if td != nil && td.GovernanceAlertsReminderInterval > 0 {
+		hours = td.GovernanceAlertsReminderInterval
+	}
+	return &RepeatRule{
+		AlertType:      "UnvotedGovernanceProposal",
+		RepeatInterval: time.Duration(hours) * time.Hour,
+	}
+}
+
+// repeatRuleFor finds the rule for an alert and destination. A rule naming the destination is preferred over one
+// that applies to all destinations, and configured rules are preferred over the built-in governance reminder.
+func repeatRuleFor(alertID, destination string) *RepeatRule {
+	var general *RepeatRule
+	for i := range repeatRules {
+		r := &repeatRules[i]
+		if !strings.HasPrefix(alertID, r.AlertType) {
+			continue
+		}
+		if r.Destination == destination {
+			return r
+		}
+		if r.Destination == "" && general == nil {
+			general = r
+		}
+	}
+	if general != nil {
+		return general
+	}
+	if gov := governanceRepeatRule(); strings.HasPrefix(alertID, gov.AlertType) {
+		return gov
+	}
+	return nil
+}
+
+// hasRepeatRule reports whether any destination could be reminded about the alert.
+func hasRepeatRule(alertID string) bool {
+	for _, r := range repeatRules {
+		if strings.HasPrefix(alertID, r.AlertType) {
+			return true
+		}
+	}
+	return strings.HasPrefix(alertID, governanceRepeatRule().AlertType)
+}
+
+// due reports whether a reminder should be sent for an alert that was last sent as described by prev.
+func (r *RepeatRule) due(prev alertMsgCache) bool {
+	if r.RepeatInterval <= 0 {
+		return false
+	}
+	if r.MaxRepeats > 0 && prev.Repeats >= r.MaxRepeats {
+		return false
+	}
+	return !prev.SentTime.After(time.Now().Add(-r.RepeatInterval))
+}
+
+// remind re-queues the active alerts that have a repeat rule and were not acknowledged, shouldNotify decides for each
+// destination if a reminder is due. The evaluators only raise an alert once, so without this nothing would be repeated.
+func (c *Config) remind() {
+	type active struct {
+		configName, id string
+		cache          alertMsgCache
+	}
+	pending := make([]active, 0)
+	alarms.notifyMux.RLock()
+	for configName, chainAlarms := range alarms.AllAlarms {
+		for id, cache := range chainAlarms {
+			if hasRepeatRule(id) && cache.AckedBy == "" {
+				pending = append(pending, active{configName: configName, id: id, cache: cache})
+			}
+		}
+	}
+	alarms.notifyMux.RUnlock()
+
+	for _, p := range pending {
+		c.chainsMux.RLock()
+		cc := c.Chains[p.configName]
+		if cc == nil {
+			c.chainsMux.RUnlock()
+			continue
+		}
+		severity := p.cache.Severity
+		if severity == "" {
+			// alarms restored from an older state file don't have a severity, use the configured one for the kind
+			// and don't guess when there is none
+			severity = cc.Alerts.Severities[string(kindOf(p.id))]
+		}
+		if severity == "" {
+			c.chainsMux.RUnlock()
+			continue
+		}
+		msg := c.newAlertMsg(p.configName, cc, p.cache.Message, severity, false, p.id)
+		msg.alertDetails = p.cache.details(p.id)
+		msg.eventID = p.cache.EventID
+		c.chainsMux.RUnlock()
+		select {
+		case c.alertChan <- msg:
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package tenderduty
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-yaml/yaml"
+)
+
+func TestRepeatRuleFromYAML(t *testing.T) {
+	c := &Config{}
+	err := yaml.Unmarshal([]byte(`
+repeat_rules:
+  - alert_type: ValidatorInactive
+    destination: pagerduty
+    repeat_interval: 30m
+    max_repeats: 4
+`), c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := RepeatRule{AlertType: "ValidatorInactive", Destination: "pagerduty", RepeatInterval: 30 * time.Minute, MaxRepeats: 4}
+	if len(c.RepeatRules) != 1 || c.RepeatRules[0] != expected {
+		t.Errorf("unexpected repeat rules %+v", c.RepeatRules)
+	}
+}
+
+func TestRepeatRuleFor(t *testing.T) {
+	originalRules, originalTd := repeatRules, td
+	repeatRules = []RepeatRule{
+		{AlertType: "ValidatorInactive", RepeatInterval: 6 * time.Hour},
+		{AlertType: "ValidatorInactive", Destination: "pagerduty", RepeatInterval: 30 * time.Minute},
+	}
+	td = &Config{GovernanceAlertsReminderInterval: 12}
+	defer func() { repeatRules, td = originalRules, originalTd }()
+
+	if r := repeatRuleFor("ValidatorInactive_val1", "pagerduty"); r == nil || r.RepeatInterval != 30*time.Minute {
+		t.Errorf("expected the pagerduty rule, got %+v", r)
+	}
+	if r := repeatRuleFor("ValidatorInactive_val1", "discord"); r == nil || r.RepeatInterval != 6*time.Hour {
+		t.Errorf("expected the general rule, got %+v", r)
+	}
+	if r := repeatRuleFor("UnvotedGovernanceProposal_val1_12", "discord"); r == nil || r.RepeatInterval != 12*time.Hour {
+		t.Errorf("expected the governance reminder, got %+v", r)
+	}
+	if r := repeatRuleFor("ChainStalled_val1", "discord"); r != nil {
+		t.Errorf("expected no rule, got %+v", r)
+	}
+
+	// a configured rule replaces the governance reminder
+	repeatRules = append(repeatRules, RepeatRule{AlertType: "UnvotedGovernanceProposal", RepeatInterval: time.Hour, MaxRepeats: 2})
+	if r := repeatRuleFor("UnvotedGovernanceProposal_val1_12", "discord"); r == nil || r.RepeatInterval != time.Hour {
+		t.Errorf("expected the configured governance rule, got %+v", r)
+	}
+}
+
+func TestRepeatRuleDue(t *testing.T) {
+	rule := &RepeatRule{RepeatInterval: time.Hour, MaxRepeats: 2}
+	if rule.due(alertMsgCache{SentTime: time.Now().Add(-30 * time.Minute)}) {
+		t.Error("reminder should not be due before the interval")
+	}
+	if !rule.due(alertMsgCache{SentTime: time.Now().Add(-2 * time.Hour), Repeats: 1}) {
+		t.Error("reminder should be due after the interval")
+	}
+	if rule.due(alertMsgCache{SentTime: time.Now().Add(-2 * time.Hour), Repeats: 2}) {
+		t.Error("reminder should not be due once the cap is reached")
+	}
+	rule.MaxRepeats = 0
+	if !rule.due(alertMsgCache{SentTime: time.Now().Add(-2 * time.Hour), Repeats: 100}) {
+		t.Error("reminders should not be capped when max_repeats is zero")
+	}
+}
+
+func TestShouldNotifyRepeat(t *testing.T) {
+	originalRules := repeatRules
+	newTestAlarmCache(t)
+	repeatRules = []RepeatRule{
+		{AlertType: "ValidatorInactive", Destination: "pagerduty", RepeatInterval: 30 * time.Minute, MaxRepeats: 1},
+		{AlertType: "ValidatorInactive", Destination: "discord", RepeatInterval: 6 * time.Hour},
+	}
+	defer func() { repeatRules = originalRules }()
+
+	msg := &alertMsg{
+		uniqueId:    "ValidatorInactive_val1",
+		message:     "jailed",
+		severity:    "critical",
+		alertConfig: &AlertConfig{},
+	}
+	sent := alertMsgCache{Message: "jailed", SentTime: time.Now().Add(-time.Hour)}
+	alarms.sentFor("pagerduty")[msg.uniqueId] = sent
+	alarms.sentFor("discord")[msg.uniqueId] = sent
+
+	if !notifyAndRecord(msg, pagerdutyNotifier{}) {
+		t.Error("pagerduty reminder should be due after 30 minutes")
+	}
+	if got := alarms.sentFor("pagerduty")[msg.uniqueId]; got.Repeats != 1 || time.Since(got.SentTime) > time.Minute {
+		t.Errorf("reminder should be recorded, got %+v", got)
+	}
+	if shouldNotify(msg, discordNotifier{}) {
+		t.Error("discord reminder should not be due before 6 hours")
+	}
+
+	// the cap stops further reminders
+	alarms.sentFor("pagerduty")[msg.uniqueId] = alertMsgCache{Message: "jailed", SentTime: time.Now().Add(-time.Hour), Repeats: 1}
+	if shouldNotify(msg, pagerdutyNotifier{}) {
+		t.Error("pagerduty reminder should be capped")
+	}
+
+	// resolving clears the reminder state
+	msg.resolved = true
+	if !notifyAndRecord(msg, pagerdutyNotifier{}) {
+		t.Error("resolution should notify")
+	}
+	if _, ok := alarms.sentFor("pagerduty")[msg.uniqueId]; ok {
+		t.Error("resolution should clear the sent state")
+	}
+}
+
+func TestRemind(t *testing.T) {
+	originalTd, originalRules := td, repeatRules
+	td = createTestConfig()
+	td.ctx, td.cancel = context.WithCancel(context.Background())
+	defer td.cancel()
+	newTestAlarmCache(t).AllAlarms = map[string]map[string]alertMsgCache{
+		"test-chain": {
+			"ValidatorInactive_testval123": {Message: "jailed", Severity: "critical", SentTime: time.Now()},
+			"ChainStalled_testval123":      {Message: "stalled", Severity: "critical", SentTime: time.Now()},
+			// restored from an older state file without a severity
+			"StakeChange_testval123": {Message: "stake dropped", SentTime: time.Now()},
+			"RPCNodeDown_testval123": {Message: "node down", SentTime: time.Now()},
+		},
+		"removed-chain": {
+			"ValidatorInactive_otherval": {Message: "jailed", Severity: "critical", SentTime: time.Now()},
+		},
+	}
+	td.Chains["test-chain"].Alerts.Severities = map[string]string{"StakeChange": "info"}
+	repeatRules = []RepeatRule{
+		{AlertType: "ValidatorInactive", RepeatInterval: time.Hour},
+		{AlertType: "StakeChange", RepeatInterval: time.Hour},
+		{AlertType: "RPCNodeDown", RepeatInterval: time.Hour},
+	}
+	defer func() { td, repeatRules = originalTd, originalRules }()
+
+	td.remind()
+
+	// the node down alarm has no severity and none is configured, so it is not reminded about
+	if len(td.alertChan) != 2 {
+		t.Fatalf("expected 2 reminders to be queued, got %d", len(td.alertChan))
+	}
+	reminders := make(map[string]*alertMsg)
+	for len(td.alertChan) > 0 {
+		msg := <-td.alertChan
+		reminders[msg.uniqueId] = msg
+	}
+	msg := reminders["ValidatorInactive_testval123"]
+	if msg == nil || msg.message != "jailed" || msg.severity != "critical" || msg.resolved {
+		t.Fatalf("unexpected reminder %+v", msg)
+	}
+	if msg.configName != "test-chain" || msg.chain != "test-chain (test-chain-1)" {
+		t.Errorf("unexpected chain on reminder %s %s", msg.configName, msg.chain)
+	}
+	if msg := reminders["StakeChange_testval123"]; msg == nil || msg.severity != "info" {
+		t.Errorf("restored alarm should be reminded with the configured severity, got %+v", msg)
+	}
+}
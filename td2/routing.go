@@ -0,0 +1,115 @@
+package tenderduty
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// RouteRule sends the alerts it matches to its destinations. The routes are evaluated in order and the first matching
+// rule decides, unless it sets Continue, in which case the following rules can add destinations. Empty fields match
+// everything. Alerts that no rule matches go to each destination whose severity_threshold allows them.
+type RouteRule struct {
+	// AlertTypes are alert kinds, for example UnvotedGovernanceProposal or RPCNodeDown
+	AlertTypes []string `yaml:"alert_types"`
+	// Chains are names of chains in the config file
+	Chains []string `yaml:"chains"`
+	// ChainID is a glob pattern for the chain-id, for example "osmosis-*"
+	ChainID    string   `yaml:"chain_id"`
+	Severities []string `yaml:"severities"`
+	// ChainLabels must all be set to the same values in the chain's labels
+	ChainLabels  map[string]string `yaml:"chain_labels"`
+	Destinations []string          `yaml:"destinations"`
+	Continue     bool              `yaml:"continue"`
+}
+
+// routeRules are the routes from the config file.
+var routeRules []RouteRule
+
+func (r *RouteRule) matches(msg *alertMsg) bool {
+	if len(r.AlertTypes) > 0 && !slices.Contains(r.AlertTypes, string(msg.kind)) {
+		return false
+	}
+	if len(r.Chains) > 0 && !slices.Contains(r.Chains, msg.configName) {
+		return false
+	}
+	if r.ChainID != "" {
+		if ok, _ := path.Match(r.ChainID, msg.chainId); !ok {
+			return false
+		}
+	}
+	if len(r.Severities) > 0 && !slices.Contains(r.Severities, msg.severity) {
+		return false
+	}
+	for k, v := range r.ChainLabels {
+		if msg.chainLabels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// routeDestinations returns the destinations selected by the routes, matched is false when no route applies.
+func routeDestinations(msg *alertMsg) (destinations []string, matched bool) {
+	for i := range routeRules {
+		r := &routeRules[i]
+		if !r.matches(msg) {
+			continue
+		}
+		matched = true
+		destinations = append(destinations, r.Destinations...)
+		if !r.Continue {
+			break
+		}
+	}
+	return
+}
+
+// routed decides if an alert goes to a destination, by the routes or else by the destination's severity threshold.
+// Escalation stages are not routed, they have their own severity threshold.
+func routed(msg *alertMsg, dest Notifier) bool {
+	if !strings.Contains(dest.Name(), escalationSeparator) {
+		if destinations, matched := routeDestinations(msg); matched {
+			return slices.Contains(destinations, dest.Name())
+		}
+	}
+	return slices.Contains(SeverityThresholdToSeverities(dest.SeverityThreshold(msg.alertConfig)), msg.severity)
+}
+
+// validateRoutes checks the routes in the config file.
+func validateRoutes(c *Config) (fatal bool, problems []string) {
+	for i, r := range c.Routes {
+		if len(r.Destinations) == 0 {
+			fatal = true
+			problems = append(problems, fmt.Sprintf("error: route %d has no destinations", i+1))
+		}
+		for _, dest := range r.Destinations {
+			if _, ok := getNotifier(dest); !ok {
+				fatal = true
+				problems = append(problems, fmt.Sprintf("error: route %d has an unknown destination %s", i+1, dest))
+			}
+		}
+		if _, err := path.Match(r.ChainID, ""); err != nil {
+			fatal = true
+			problems = append(problems, fmt.Sprintf("error: route %d has an invalid chain_id pattern %q", i+1, r.ChainID))
+		}
+		for _, severity := range r.Severities {
+			if !slices.Contains([]string{"critical", "warning", "info"}, severity) {
+				fatal = true
+				problems = append(problems, fmt.Sprintf("error: route %d has an unknown severity %s", i+1, severity))
+			}
+		}
+		for _, chain := range r.Chains {
+			if c.Chains[chain] == nil {
+				problems = append(problems, fmt.Sprintf("warning: route %d has an unknown chain %s", i+1, chain))
+			}
+		}
+		for _, kind := range r.AlertTypes {
+			if !slices.Contains(alertKinds, alertKind(kind)) {
+				problems = append(problems, fmt.Sprintf("warning: route %d has an unknown alert type %s", i+1, kind))
+			}
+		}
+	}
+	return
+}
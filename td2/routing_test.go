@@ -0,0 +1,108 @@
+package tenderduty
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-yaml/yaml"
+)
+
+func TestRouteRuleFromYAML(t *testing.T) {
+	c := &Config{}
+	err := yaml.Unmarshal([]byte(`
+routes:
+  - alert_types: [UnvotedGovernanceProposal]
+    destinations: [slack]
+  - chain_id: "osmosis-*"
+    severities: [critical]
+    chain_labels:
+      team: infra
+    destinations: [telegram, pagerduty]
+    continue: true
+`), c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(c.Routes) != 2 || c.Routes[0].AlertTypes[0] != "UnvotedGovernanceProposal" || c.Routes[1].ChainLabels["team"] != "infra" ||
+		len(c.Routes[1].Destinations) != 2 || !c.Routes[1].Continue {
+		t.Errorf("unexpected routes %+v", c.Routes)
+	}
+}
+
+func TestRouted(t *testing.T) {
+	original := routeRules
+	defer func() { routeRules = original }()
+	routeRules = []RouteRule{
+		{AlertTypes: []string{"UnvotedGovernanceProposal"}, Destinations: []string{"slack"}},
+		{AlertTypes: []string{"RPCNodeDown"}, ChainLabels: map[string]string{"team": "infra"}, Destinations: []string{"telegram"}, Continue: true},
+		{ChainID: "osmosis-*", Severities: []string{"critical"}, Destinations: []string{"pagerduty"}},
+	}
+	cfg := &AlertConfig{
+		Slack:     SlackConfig{SeverityThreshold: "info"},
+		Telegram:  TeleConfig{SeverityThreshold: "info"},
+		Pagerduty: PDConfig{SeverityThreshold: "critical"},
+	}
+	msg := func(kind alertKind, chainId, severity string, labels map[string]string) *alertMsg {
+		return &alertMsg{chainId: chainId, severity: severity, chainLabels: labels, alertDetails: alertDetails{kind: kind}, alertConfig: cfg}
+	}
+
+	tests := []struct {
+		name     string
+		msg      *alertMsg
+		expected []string
+	}{
+		{"governance only to slack", msg(kindUnvotedGovernanceProposal, "osmosis-1", "warning", nil), []string{"slack"}},
+		{"node down to infra and on to pagerduty", msg(kindRPCNodeDown, "osmosis-1", "critical", map[string]string{"team": "infra"}), []string{"telegram", "pagerduty"}},
+		{"node down without the label", msg(kindRPCNodeDown, "osmosis-1", "critical", nil), []string{"pagerduty"}},
+		{"chain_id glob", msg(kindChainStalled, "osmo-test-5", "critical", nil), []string{"slack", "telegram", "pagerduty"}},
+		{"no route uses severity thresholds", msg(kindChainStalled, "cosmoshub-4", "warning", nil), []string{"slack", "telegram"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, n := range []Notifier{slackNotifier{}, telegramNotifier{}, pagerdutyNotifier{}} {
+				if routed(tt.msg, n) {
+					got = append(got, n.Name())
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	// escalation stages are not routed
+	stage := escalationNotifier{Notifier: slackNotifier{}, stage: 1, cfg: cfg}
+	if !routed(msg(kindChainStalled, "osmosis-1", "critical", nil), stage) {
+		t.Error("escalation stage should use its severity threshold")
+	}
+}
+
+func TestValidateRoutes(t *testing.T) {
+	c := &Config{
+		Chains: map[string]*ChainConfig{"osmosis": {}},
+		Routes: []RouteRule{
+			{Chains: []string{"osmosis"}, Destinations: []string{"discord"}},
+			{Destinations: []string{"carrier-pigeon"}},
+			{ChainID: "[osmosis", Destinations: []string{"slack"}},
+			{Severities: []string{"urgent"}, Destinations: []string{"slack"}},
+			{},
+			{AlertTypes: []string{"Unknown"}, Chains: []string{"juno"}, Destinations: []string{"slack"}},
+		},
+	}
+	fatal, problems := validateRoutes(c)
+	if !fatal {
+		t.Error("invalid routes should be fatal")
+	}
+	expected := []string{
+		"error: route 2 has an unknown destination carrier-pigeon",
+		`error: route 3 has an invalid chain_id pattern "[osmosis"`,
+		"error: route 4 has an unknown severity urgent",
+		"error: route 5 has no destinations",
+		"warning: route 6 has an unknown chain juno",
+		"warning: route 6 has an unknown alert type Unknown",
+	}
+	if strings.Join(problems, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected problems %v", problems)
+	}
+}
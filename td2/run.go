@@ -36,29 +36,36 @@ func Run(configFile, stateFile, chainConfigDirectory string, password *string, d
 		for {
 			select {
 			case alert := <-td.alertChan:
-				go func(msg *alertMsg) {
-					var e error
-					e = notifyPagerduty(msg)
-					if e != nil {
-						l(slog.LevelWarn, msg.chain, "error sending alert to pagerduty", e.Error())
-					}
-					e = notifyDiscord(msg)
-					if e != nil {
-						l(slog.LevelWarn, msg.chain, "error sending alert to discord", e.Error())
-					}
-					e = notifyTg(msg)
-					if e != nil {
-						l(slog.LevelWarn, msg.chain, "error sending alert to telegram", e.Error())
-					}
-					e = notifySlack(msg)
-					if e != nil {
-						l(slog.LevelWarn, msg.chain, "error sending alert to slack", e.Error())
-					}
-					e = notifyWebhook(msg)
-					if e != nil {
-						l(slog.LevelWarn, msg.chain, "error sending alert to webhook", e.Error())
-					}
-				}(alert)
+				go notify(alert)
+			case <-td.ctx.Done():
+				return
+			}
+		}
+	}()
+
+	if settings := historySettings(stateFile); settings.enabled() {
+		if history, err = openHistory(settings.Path); err != nil {
+			l(slog.LevelWarn, fmt.Sprintf("could not open the alert history at %s, continuing without it: %s", settings.Path, err))
+		} else {
+			go history.run(td.ctx, settings.Retention)
+		}
+	}
+
+	go outbox.run(td.ctx)
+	go td.runDigests(td.ctx)
+
+	// the evaluators only raise an alert once, reminders and escalations for alerts that are still active, the final
+	// state of alerts that stopped flapping, and alerts whose silence ended, are queued from here
+	go func() {
+		tick := time.NewTicker(time.Minute)
+		defer tick.Stop()
+		for {
+			select {
+			case <-tick.C:
+				td.remind()
+				td.escalate()
+				td.settleFlapping()
+				td.deliverUnsilenced()
 			case <-td.ctx.Done():
 				return
 			}
@@ -66,6 +73,11 @@ func Run(configFile, stateFile, chainConfigDirectory string, password *string, d
 	}()
 
 	if td.EnableDash {
+		dash.HandleAPI("/api/silences", silencesHandler)
+		dash.HandleAPI("/api/outbox", outboxHandler)
+		dash.HandleAPI("/api/alerts/history", historyHandler)
+		dash.HandleAPI("/api/actions/slack", slackActionHandler)
+		dash.HandleAPI("/api/actions/discord", discordActionHandler)
 		go dash.Serve(td.Listen, td.updateChan, td.logChan, td.HideLogs, devMode)
 		l(slog.LevelInfo, "starting dashboard on ", td.Listen)
 	} else {
@@ -85,6 +97,10 @@ func Run(configFile, stateFile, chainConfigDirectory string, password *string, d
 		}()
 	}
 
+	if td.TelegramBot.Enabled {
+		go td.runTelegramBot(td.ctx)
+	}
+
 	// tenderduty health checks:
 	if td.Healthcheck.Enabled {
 		td.pingHealthcheck()
@@ -122,6 +138,11 @@ func Run(configFile, stateFile, chainConfigDirectory string, password *string, d
 				if e != nil {
 					l(slog.LevelError, "🛑", cc.ChainId, e)
 				}
+				if td.Bootstrap.Enabled && cc.gridEmpty() && cc.valInfo != nil {
+					if e = cc.bootstrap(td.ctx); e != nil {
+						l(slog.LevelWarn, fmt.Sprintf("⏪ %-12s could not fetch the recent blocks: %s", cc.ChainId, e))
+					}
+				}
 				cc.WsRun()
 				l(slog.LevelWarn, cc.ChainId, "🌀 websocket exited! Restarting monitoring")
 				time.Sleep(5 * time.Second)
@@ -176,6 +197,8 @@ func saveOnExit(stateFile string, saved chan any) {
 			Alarms:    alarms,
 			Blocks:    blocks,
 			NodesDown: nodesDown,
+			Silences:  silences.runtime(),
+			Outbox:    outbox.snapshot(),
 		})
 		if e != nil {
 			slog.Error("failed to marshal state", "err", e)
@@ -0,0 +1,288 @@
+package tenderduty
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Silence mutes notifications for matching alerts between Start and End, for example during a planned upgrade.
+// Silenced alerts are still tracked and shown on the dashboard, they are only kept from the destinations.
+// Every matcher that is set must match, and at least one must be set.
+type Silence struct {
+	ID string `yaml:"id" json:"id"`
+	// Chain matches either the name of the chain in the config file or its chain-id
+	Chain string `yaml:"chain" json:"chain,omitempty"`
+	// AlertType is matched as a prefix of the alert ID, for example ChainStalled or ConsecutiveBlocksMissed
+	AlertType string `yaml:"alert_type" json:"alert_type,omitempty"`
+	// AlertID matches a single alert by its full ID
+	AlertID string `yaml:"alert_id" json:"alert_id,omitempty"`
+	// Valoper matches the validator's operator address
+	Valoper string    `yaml:"valoper" json:"valoper,omitempty"`
+	Start   time.Time `yaml:"start" json:"start"`
+	End     time.Time `yaml:"end" json:"end"`
+	Comment string    `yaml:"comment" json:"comment,omitempty"`
+
+	// Duration can be used instead of End when creating a silence through the API, for example "2h"
+	Duration string `yaml:"-" json:"duration,omitempty"`
+
+	// fromConfig silences are loaded from the config file at start, and are not saved in the state file
+	fromConfig bool
+}
+
+// validate fills in defaults and checks that the silence can match something.
+func (s *Silence) validate() error {
+	if s.Chain == "" && s.AlertType == "" && s.AlertID == "" && s.Valoper == "" {
+		return errors.New("a silence needs at least one of chain, alert_type, alert_id or valoper")
+	}
+	if s.Start.IsZero() {
+		s.Start = time.Now()
+	}
+	if s.End.IsZero() && s.Duration != "" {
+		d, err := time.ParseDuration(s.Duration)
+		if err != nil {
+			return fmt.Errorf("invalid duration %s: %w", s.Duration, err)
+		}
+		s.End = s.Start.Add(d)
+	}
+	s.Duration = ""
+	if !s.End.After(s.Start) {
+		return errors.New("a silence must end after it starts")
+	}
+	return nil
+}
+
+// matches reports whether the silence applies to the alert at time t.
+func (s *Silence) matches(chain, chainId, alertID, valoper string, t time.Time) bool {
+	if t.Before(s.Start) || !t.Before(s.End) {
+		return false
+	}
+	if s.Chain != "" && s.Chain != chain && s.Chain != chainId {
+		return false
+	}
+	if s.AlertType != "" && !strings.HasPrefix(alertID, s.AlertType) {
+		return false
+	}
+	if s.AlertID != "" && s.AlertID != alertID {
+		return false
+	}
+	if s.Valoper != "" && s.Valoper != valoper {
+		return false
+	}
+	return true
+}
+
+type silenceStore struct {
+	mux      sync.RWMutex
+	silences map[string]*Silence
+}
+
+func newSilenceStore() *silenceStore {
+	return &silenceStore{silences: make(map[string]*Silence)}
+}
+
+// silences holds both the silences from the config file and those added at runtime.
+var silences = newSilenceStore()
+
+// add validates the silence and stores it, an ID is generated if it does not have one.
+func (ss *silenceStore) add(s *Silence) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+	ss.mux.Lock()
+	defer ss.mux.Unlock()
+	if s.ID == "" {
+		b := make([]byte, 8)
+		if _, err := rand.Read(b); err != nil {
+			return err
+		}
+		s.ID = hex.EncodeToString(b)
+	}
+	ss.silences[s.ID] = s
+	return nil
+}
+
+// remove deletes a silence, it returns false if it did not exist.
+func (ss *silenceStore) remove(id string) bool {
+	ss.mux.Lock()
+	defer ss.mux.Unlock()
+	if ss.silences[id] == nil {
+		return false
+	}
+	delete(ss.silences, id)
+	return true
+}
+
+// list returns the silences that have not expired, ordered by when they start. Expired silences are pruned.
+func (ss *silenceStore) list() []*Silence {
+	ss.mux.Lock()
+	defer ss.mux.Unlock()
+	now := time.Now()
+	result := make([]*Silence, 0, len(ss.silences))
+	for id, s := range ss.silences {
+		if !s.End.After(now) {
+			delete(ss.silences, id)
+			continue
+		}
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Start.Equal(result[j].Start) {
+			return result[i].ID < result[j].ID
+		}
+		return result[i].Start.Before(result[j].Start)
+	})
+	return result
+}
+
+// runtime returns the unexpired silences that were added through the API, these are persisted in the state file.
+func (ss *silenceStore) runtime() []*Silence {
+	result := make([]*Silence, 0)
+	for _, s := range ss.list() {
+		if !s.fromConfig {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// match returns the first active silence for an alert, or nil if it is not silenced.
+func (ss *silenceStore) match(chain, chainId, alertID, valoper string) *Silence {
+	ss.mux.RLock()
+	defer ss.mux.RUnlock()
+	now := time.Now()
+	for _, s := range ss.silences {
+		if s.matches(chain, chainId, alertID, valoper, now) {
+			return s
+		}
+	}
+	return nil
+}
+
+// silenced reports whether notifications for the alert are currently muted.
+func (a *alertMsg) silenced() *Silence {
+	return silences.match(a.configName, a.chainId, a.uniqueId, a.valoperAddress)
+}
+
+// silence keeps an alert that was held back by a silence, so that it is delivered if it is still active when the
+// silence ends. The caller must hold notifyMux.
+func (a *alarmCache) silence(msg *alertMsg) {
+	if a.silenced == nil {
+		a.silenced = make(map[string]map[string]*alertMsg)
+	}
+	if a.silenced[msg.configName] == nil {
+		a.silenced[msg.configName] = make(map[string]*alertMsg)
+	}
+	a.silenced[msg.configName][msg.uniqueId] = msg
+}
+
+// releaseSilenced returns the silenced alerts that are still active but no longer silenced, these need to be
+// delivered now. Alerts that resolved while silenced are forgotten.
+func (a *alarmCache) releaseSilenced() []*alertMsg {
+	a.notifyMux.Lock()
+	defer a.notifyMux.Unlock()
+	released := make([]*alertMsg, 0)
+	for configName, chainSilenced := range a.silenced {
+		for id, msg := range chainSilenced {
+			if _, active := a.AllAlarms[configName][id]; !active {
+				delete(chainSilenced, id)
+				continue
+			}
+			if msg.silenced() != nil {
+				continue
+			}
+			delete(chainSilenced, id)
+			released = append(released, msg)
+		}
+	}
+	return released
+}
+
+// deliverUnsilenced queues the alerts whose silence ended, or was removed, while they were still active.
+func (c *Config) deliverUnsilenced() {
+	for _, msg := range alarms.releaseSilenced() {
+		l(slog.LevelInfo, fmt.Sprintf("🔔 silence ended for %s on %s, notifying", msg.uniqueId, msg.chain))
+		select {
+		case c.alertChan <- msg:
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+// silencesHandler implements the dashboard's /api/silences endpoint:
+//
+//	GET    lists the active and pending silences
+//	POST   creates a silence from a JSON body, either end or duration must be set
+//	DELETE removes the silence given in the id query parameter
+//
+// Changing silences requires the dashboard_api_token as a bearer token, and is disabled if it is not set. Listing
+// them is open unless logs are hidden on the dashboard.
+func silencesHandler(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet || td.HideLogs {
+		if !apiAuthorized(request) {
+			http.Error(writer, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	switch request.Method {
+	case http.MethodGet:
+		writeJSON(writer, http.StatusOK, silences.list())
+
+	case http.MethodPost:
+		s := &Silence{}
+		if err := json.NewDecoder(http.MaxBytesReader(writer, request.Body, 1<<16)).Decode(s); err != nil {
+			http.Error(writer, "invalid silence: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.fromConfig = false
+		if err := silences.add(s); err != nil {
+			http.Error(writer, "invalid silence: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		l(slog.LevelInfo, fmt.Sprintf("🔕 added silence %s until %s", s.ID, s.End.Format(time.RFC3339)))
+		writeJSON(writer, http.StatusCreated, s)
+
+	case http.MethodDelete:
+		id := request.URL.Query().Get("id")
+		if !silences.remove(id) {
+			http.Error(writer, "silence not found", http.StatusNotFound)
+			return
+		}
+		l(slog.LevelInfo, "🔔 removed silence "+id)
+		writer.WriteHeader(http.StatusNoContent)
+
+	default:
+		writer.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(writer, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// apiAuthorized checks the request's bearer token against dashboard_api_token.
+func apiAuthorized(request *http.Request) bool {
+	if td.DashboardAPIToken == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(request.Header.Get("Authorization")), []byte("Bearer "+td.DashboardAPIToken)) == 1
+}
+
+func writeJSON(writer http.ResponseWriter, status int, v any) {
+	j, err := json.Marshal(v)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	_, _ = writer.Write(j)
+}
@@ -0,0 +1,320 @@
+package tenderduty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-yaml/yaml"
+)
+
+func TestSilenceMatches(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		silence  Silence
+		chain    string
+		chainId  string
+		alertID  string
+		valoper  string
+		expected bool
+	}{
+		{
+			name:     "chain name matches",
+			silence:  Silence{Chain: "osmosis", Start: now.Add(-time.Hour), End: now.Add(time.Hour)},
+			chain:    "osmosis",
+			chainId:  "osmosis-1",
+			alertID:  "ChainStalled_osmovaloper1",
+			expected: true,
+		},
+		{
+			name:     "chain id matches",
+			silence:  Silence{Chain: "osmosis-1", Start: now.Add(-time.Hour), End: now.Add(time.Hour)},
+			chain:    "osmosis",
+			chainId:  "osmosis-1",
+			alertID:  "ChainStalled_osmovaloper1",
+			expected: true,
+		},
+		{
+			name:     "alert type is a prefix",
+			silence:  Silence{AlertType: "ConsecutiveBlocksMissed", Start: now.Add(-time.Hour), End: now.Add(time.Hour)},
+			chain:    "osmosis",
+			alertID:  "ConsecutiveBlocksMissed_osmovaloper1",
+			expected: true,
+		},
+		{
+			name:     "alert id must match exactly",
+			silence:  Silence{AlertID: "ConsecutiveBlocksMissed_osmovaloper1", Start: now.Add(-time.Hour), End: now.Add(time.Hour)},
+			chain:    "osmosis",
+			alertID:  "ConsecutiveBlocksMissed_osmovaloper12",
+			expected: false,
+		},
+		{
+			name:     "all matchers must match",
+			silence:  Silence{Chain: "osmosis", AlertType: "ChainStalled", Start: now.Add(-time.Hour), End: now.Add(time.Hour)},
+			chain:    "osmosis",
+			alertID:  "ConsecutiveBlocksMissed_osmovaloper1",
+			expected: false,
+		},
+		{
+			name:     "valoper mismatch",
+			silence:  Silence{Valoper: "osmovaloper2", Start: now.Add(-time.Hour), End: now.Add(time.Hour)},
+			chain:    "osmosis",
+			alertID:  "ChainStalled_osmovaloper1",
+			valoper:  "osmovaloper1",
+			expected: false,
+		},
+		{
+			name:     "not started yet",
+			silence:  Silence{Chain: "osmosis", Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)},
+			chain:    "osmosis",
+			alertID:  "ChainStalled_osmovaloper1",
+			expected: false,
+		},
+		{
+			name:     "expired",
+			silence:  Silence{Chain: "osmosis", Start: now.Add(-2 * time.Hour), End: now.Add(-time.Hour)},
+			chain:    "osmosis",
+			alertID:  "ChainStalled_osmovaloper1",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.silence.matches(tt.chain, tt.chainId, tt.alertID, tt.valoper, now); result != tt.expected {
+				t.Errorf("matches() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSilenceValidate(t *testing.T) {
+	s := &Silence{Chain: "osmosis", Duration: "2h"}
+	if err := s.validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Start.IsZero() || s.End.Sub(s.Start) != 2*time.Hour {
+		t.Errorf("expected a two hour silence starting now, got %s - %s", s.Start, s.End)
+	}
+
+	if err := (&Silence{Start: time.Now(), End: time.Now().Add(time.Hour)}).validate(); err == nil {
+		t.Error("expected an error for a silence without matchers")
+	}
+	if err := (&Silence{Chain: "osmosis", Start: time.Now()}).validate(); err == nil {
+		t.Error("expected an error for a silence without an end")
+	}
+	if err := (&Silence{Chain: "osmosis", Duration: "soon"}).validate(); err == nil {
+		t.Error("expected an error for an invalid duration")
+	}
+}
+
+func TestSilenceFromYAML(t *testing.T) {
+	c := &Config{}
+	err := yaml.Unmarshal([]byte(`
+silences:
+  - id: upgrade
+    chain: osmosis
+    alert_type: ChainStalled
+    start: 2030-01-02T15:00:00Z
+    end: 2030-01-02T17:00:00Z
+    comment: v25 upgrade
+`), c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(c.Silences) != 1 {
+		t.Fatalf("expected 1 silence, got %d", len(c.Silences))
+	}
+	s := c.Silences[0]
+	if s.ID != "upgrade" || s.Chain != "osmosis" || s.AlertType != "ChainStalled" || s.Comment != "v25 upgrade" {
+		t.Errorf("unexpected silence %+v", s)
+	}
+	if !s.Start.Equal(time.Date(2030, 1, 2, 15, 0, 0, 0, time.UTC)) || !s.End.Equal(time.Date(2030, 1, 2, 17, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected silence window %s - %s", s.Start, s.End)
+	}
+}
+
+func TestShouldNotifySilenced(t *testing.T) {
+	originalSilences := silences
+	newTestAlarmCache(t)
+	silences = newSilenceStore()
+	defer func() { silences = originalSilences }()
+
+	if err := silences.add(&Silence{Chain: "osmosis", AlertType: "ChainStalled", Duration: "1h"}); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := &alertMsg{
+		configName:     "osmosis",
+		chainId:        "osmosis-1",
+		valoperAddress: "osmovaloper1",
+		uniqueId:       "ChainStalled_osmovaloper1",
+		severity:       "critical",
+		alertConfig:    &AlertConfig{},
+	}
+	if shouldNotify(msg, discordNotifier{}) {
+		t.Error("silenced alert should not notify")
+	}
+	if _, ok := alarms.sentFor("discord")[msg.uniqueId]; ok {
+		t.Error("silenced alert should not be recorded as sent")
+	}
+
+	// a resolution for an alert that was never sent is not delivered either
+	msg.resolved = true
+	if shouldNotify(msg, discordNotifier{}) {
+		t.Error("resolution of a silenced alert should not notify")
+	}
+
+	// an alert delivered before the silence started is still resolved
+	alarms.sentFor("discord")[msg.uniqueId] = alertMsgCache{Message: "stalled", SentTime: time.Now().Add(-2 * time.Hour)}
+	if !shouldNotify(msg, discordNotifier{}) {
+		t.Error("resolution of an alert sent before the silence should notify")
+	}
+
+	other := &alertMsg{
+		configName:  "osmosis",
+		uniqueId:    "ConsecutiveBlocksMissed_osmovaloper1",
+		severity:    "critical",
+		alertConfig: &AlertConfig{},
+	}
+	if !shouldNotify(other, discordNotifier{}) {
+		t.Error("alert not matching the silence should notify")
+	}
+}
+
+func TestSilencedAlertDeliveredWhenSilenceEnds(t *testing.T) {
+	setupOutboxTest(t)
+	td.ctx, td.cancel = context.WithCancel(context.Background())
+	defer td.cancel()
+	originalSilences := silences
+	silences = newSilenceStore()
+	t.Cleanup(func() { silences = originalSilences })
+	s := &Silence{Chain: "test-chain", AlertType: "ChainStalled", Duration: "1h"}
+	if err := silences.add(s); err != nil {
+		t.Fatal(err)
+	}
+
+	stalledID, missedID := "ChainStalled_testval123", "ChainStalled_testval123_other"
+	td.alert("test-chain", "stalled", "critical", false, &stalledID, alertDetails{})
+	td.alert("test-chain", "stalled again", "critical", false, &missedID, alertDetails{})
+	for _, msg := range drainAlerts() {
+		if shouldNotify(msg, discordNotifier{}) {
+			t.Fatal("silenced alert should not notify")
+		}
+	}
+	td.deliverUnsilenced()
+	if msgs := drainAlerts(); len(msgs) != 0 {
+		t.Fatalf("nothing should be delivered while the silence is active, got %+v", msgs)
+	}
+
+	// one of the alerts resolves during the silence and is forgotten
+	td.alert("test-chain", "stalled again", "critical", true, &missedID, alertDetails{})
+	drainAlerts()
+	silences.remove(s.ID)
+	td.deliverUnsilenced()
+	msgs := drainAlerts()
+	if len(msgs) != 1 || msgs[0].uniqueId != stalledID || !shouldNotify(msgs[0], discordNotifier{}) {
+		t.Fatalf("the alert that is still active should be delivered once the silence ends, got %+v", msgs)
+	}
+	td.deliverUnsilenced()
+	if msgs = drainAlerts(); len(msgs) != 0 {
+		t.Errorf("the alert should only be released once, got %+v", msgs)
+	}
+}
+
+func TestSilencesHandler(t *testing.T) {
+	originalTd, originalSilences := td, silences
+	td = &Config{DashboardAPIToken: "secret"}
+	silences = newSilenceStore()
+	defer func() { td, silences = originalTd, originalSilences }()
+
+	do := func(method, target, token string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, bytes.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		silencesHandler(rec, req)
+		return rec
+	}
+
+	body := []byte(`{"chain":"osmosis","alert_type":"ChainStalled","duration":"2h","comment":"upgrade"}`)
+	if rec := do(http.MethodPost, "/api/silences", "", body); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a token, got %d", rec.Code)
+	}
+	if rec := do(http.MethodPost, "/api/silences", "wrong", body); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 with the wrong token, got %d", rec.Code)
+	}
+	if rec := do(http.MethodPost, "/api/silences", "secret", []byte(`{"duration":"2h"}`)); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a silence without matchers, got %d", rec.Code)
+	}
+
+	rec := do(http.MethodPost, "/api/silences", "secret", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	created := &Silence{}
+	if err := json.Unmarshal(rec.Body.Bytes(), created); err != nil {
+		t.Fatal(err)
+	}
+	if created.ID == "" || created.End.Sub(created.Start) != 2*time.Hour {
+		t.Errorf("unexpected silence %+v", created)
+	}
+
+	rec = do(http.MethodGet, "/api/silences", "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	listed := make([]*Silence, 0)
+	if err := json.Unmarshal(rec.Body.Bytes(), &listed); err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 1 || listed[0].ID != created.ID {
+		t.Errorf("unexpected silences %s", rec.Body.String())
+	}
+	if len(silences.runtime()) != 1 {
+		t.Error("silence added through the API should be saved in the state file")
+	}
+
+	if rec := do(http.MethodDelete, "/api/silences?id="+created.ID, "secret", nil); rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", rec.Code)
+	}
+	if rec := do(http.MethodDelete, "/api/silences?id="+created.ID, "secret", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+
+	// with no token configured the API is read-only, and when logs are hidden listing needs the token too
+	td = &Config{HideLogs: true}
+	if rec := do(http.MethodPost, "/api/silences", "", body); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 when no token is configured, got %d", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/api/silences", "", nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 when logs are hidden, got %d", rec.Code)
+	}
+}
+
+func TestGetAlarmsShowsSilenced(t *testing.T) {
+	originalTd, originalSilences := td, silences
+	td = &Config{Chains: map[string]*ChainConfig{"osmosis": {ChainId: "osmosis-1", ValAddress: "osmovaloper1"}}}
+	newTestAlarmCache(t).AllAlarms = map[string]map[string]alertMsgCache{
+		"osmosis": {"ChainStalled_osmovaloper1": {Message: "stalled"}},
+	}
+	silences = newSilenceStore()
+	defer func() { td, silences = originalTd, originalSilences }()
+
+	if got := getAlarms("osmosis"); got != "🚨 stalled\n" {
+		t.Errorf("unexpected alarms %q", got)
+	}
+	if err := silences.add(&Silence{Valoper: "osmovaloper1", Duration: "1h"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := getAlarms("osmosis"); !strings.HasPrefix(got, "🔕 ") {
+		t.Errorf("silenced alarm should be marked, got %q", got)
+	}
+}
@@ -9,6 +9,7 @@ import { DataService } from './data-service.js';
 import { GridRenderer } from './grid-renderer.js';
 import { TableRenderer } from './table-renderer.js';
 import { LogManager } from './log-manager.js';
+import { OutboxManager } from './outbox-manager.js';
 import { WebSocketManager } from './websocket-manager.js';
 import { WS_MESSAGE_TYPES } from './constants.js';
 
@@ -20,6 +21,7 @@ class App {
     this.gridRenderer = new GridRenderer();
     this.tableRenderer = new TableRenderer();
     this.logManager = new LogManager();
+    this.outboxManager = new OutboxManager(this.dataService);
     this.wsManager = new WebSocketManager();
     
     // Connect components
@@ -84,6 +86,9 @@ class App {
       
       // Connect to websocket for real-time updates
       this.wsManager.connect();
+
+      // Show notifications that could not be delivered
+      this.outboxManager.start();
       
     } catch (error) {
       console.error('Failed to initialize application:', error);
@@ -11,6 +11,7 @@ export const BLOCK_STATUS = {
   SIGNED: 3,
   PROPOSED: 4,
   EMPTY_PROPOSED: 5,
+  MISSED_PROPOSAL: 6,
   NO_DATA: -1
 };
 
@@ -45,6 +46,7 @@ export const API = {
   LOGS_ENABLED: 'logsenabled',
   STATE: 'state',
   LOGS: 'logs',
+  OUTBOX: 'api/outbox',
   WEBSOCKET: 'ws'
 };
 
@@ -54,5 +56,8 @@ export const WS_MESSAGE_TYPES = {
   UPDATE: 'update'
 };
 
+// How often the outbox is refreshed, in milliseconds
+export const OUTBOX_POLL_INTERVAL = 30000;
+
 // Maximum number of log entries to keep
 export const MAX_LOG_ENTRIES = 256; 
\ No newline at end of file
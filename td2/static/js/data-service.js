@@ -66,6 +66,23 @@ export class DataService {
     return await this._fetchData(API.LOGS);
   }
 
+  /**
+   * Fetch undelivered notifications and dead letters
+   * @returns {Promise<Object>} Outbox contents, null if it is not available
+   */
+  async fetchOutbox() {
+    try {
+      const response = await fetch(this._getUrl(API.OUTBOX), this.fetchOptions);
+      if (!response.ok) {
+        return null;
+      }
+      return await response.json();
+    } catch (error) {
+      console.error(`Error fetching ${API.OUTBOX}:`, error);
+      return null;
+    }
+  }
+
   /**
    * Load initial state and logs
    * @returns {Promise<Object>} Combined state data
@@ -159,6 +159,10 @@ export class GridRenderer {
         block.classList.add('status-empty-proposed');
         statusText = 'Proposed (Empty)';
         break;
+      case BLOCK_STATUS.MISSED_PROPOSAL: // 6
+        block.classList.add('status-missed-proposal');
+        statusText = 'Missed Proposal';
+        break;
       case BLOCK_STATUS.SIGNED: // 3
         block.classList.add('status-signed');
         statusText = 'Signed';
@@ -0,0 +1,74 @@
+/**
+ * OutboxManager
+ * Shows notifications that could not be delivered
+ */
+import { OUTBOX_POLL_INTERVAL } from './constants.js';
+
+export class OutboxManager {
+  constructor(dataService) {
+    this.dataService = dataService;
+    this.container = document.getElementById('outboxContainer');
+    this.body = document.getElementById('deadLetterTable');
+    this.summary = document.getElementById('outboxSummary');
+  }
+
+  /**
+   * Load the outbox and keep refreshing it
+   */
+  start() {
+    this.refresh();
+    setInterval(() => {
+      if (document.visibilityState !== 'hidden') {
+        this.refresh();
+      }
+    }, OUTBOX_POLL_INTERVAL);
+  }
+
+  /**
+   * Fetch the outbox and update the display
+   */
+  async refresh() {
+    const outbox = await this.dataService.fetchOutbox();
+    this.render(outbox);
+  }
+
+  /**
+   * Render the dead letters, the section is hidden while there is nothing to show
+   * @param {Object} outbox - Outbox contents from the API
+   */
+  render(outbox) {
+    if (!this.container) return;
+    const pending = (outbox && outbox.pending) || [];
+    const deadLetters = (outbox && outbox.dead_letters) || [];
+    this.container.hidden = pending.length === 0 && deadLetters.length === 0;
+    if (this.container.hidden) return;
+
+    this.summary.textContent = `${pending.length} waiting to be retried, ${deadLetters.length} undeliverable`;
+    this.body.replaceChildren(...deadLetters.slice().reverse().map((entry) => this._row(entry)));
+  }
+
+  /**
+   * Build a table row for a dead letter
+   * @param {Object} entry - Dead letter
+   * @returns {HTMLTableRowElement} Table row
+   * @private
+   */
+  _row(entry) {
+    const row = document.createElement('tr');
+    const cells = [
+      new Date(entry.created).toLocaleString(),
+      entry.destination,
+      entry.config_name,
+      `${entry.resolved ? 'resolved: ' : ''}${entry.message}`,
+      entry.attempts,
+      entry.last_error || '',
+      entry.id,
+    ];
+    for (const value of cells) {
+      const cell = document.createElement('td');
+      cell.textContent = value;
+      row.appendChild(cell);
+    }
+    return row;
+  }
+}
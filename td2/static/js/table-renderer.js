@@ -76,6 +76,11 @@ export class TableRenderer {
       }
     }
 
+    // Alerts waiting for their `for` duration have not fired yet, they are only mentioned
+    if (status.pending_alerts > 0) {
+      statusText += ` (${_.escape(status.pending_alerts)} pending)`;
+    }
+
     // Handle 'not connected' separately for status text/class if needed, though covered by 'Inactive'
     if (status.moniker === "not connected") {
       statusClass = "status-indicator-gray";
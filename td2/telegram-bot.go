@@ -0,0 +1,284 @@
+package tenderduty
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TelegramBotConfig lets allow-listed users query and manage alerts with commands in the Telegram chat used for
+// alerts. The API key and channel default to the ones in default_alert_config.
+type TelegramBotConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	ApiKey  string `yaml:"api_key"`
+	Channel string `yaml:"channel"`
+	// AllowedUsers are the numeric Telegram user IDs that may use the commands
+	AllowedUsers []int64 `yaml:"allowed_users"`
+	// AllowedSignatures are the author signatures that may use the commands in a channel, where Telegram does not
+	// say which user posted. Channels need "Sign messages" enabled for posts to have a signature.
+	AllowedSignatures []string `yaml:"allowed_signatures"`
+}
+
+// telegramBotSettings returns the bot config with the API key and channel taken from the default telegram alert
+// settings when they are not set.
+func (c *Config) telegramBotSettings() TelegramBotConfig {
+	bot := c.TelegramBot
+	if bot.ApiKey == "" {
+		bot.ApiKey = c.DefaultAlertConfig.Telegram.ApiKey
+	}
+	if bot.Channel == "" {
+		bot.Channel = c.DefaultAlertConfig.Telegram.Channel
+	}
+	return bot
+}
+
+// isChat reports whether a chat is the configured channel, which is either a numeric chat ID or an @username.
+func (b TelegramBotConfig) isChat(chat *tgbotapi.Chat) bool {
+	if chat == nil {
+		return false
+	}
+	if id, err := strconv.ParseInt(b.Channel, 10, 64); err == nil {
+		return chat.ID == id
+	}
+	return chat.UserName != "" && strings.EqualFold(strings.TrimPrefix(b.Channel, "@"), chat.UserName)
+}
+
+func (b TelegramBotConfig) allowed(user *tgbotapi.User) bool {
+	return user != nil && slices.Contains(b.AllowedUsers, user.ID)
+}
+
+// sender returns who sent a command and whether they may use the bot. In a group that is the user, a post in a
+// channel has no user and is matched by its author signature instead.
+func (b TelegramBotConfig) sender(m *tgbotapi.Message) (string, bool) {
+	if m.From == nil && m.SenderChat != nil && m.SenderChat.ID == m.Chat.ID {
+		if m.AuthorSignature == "" {
+			return "unsigned channel post", false
+		}
+		return m.AuthorSignature, slices.Contains(b.AllowedSignatures, m.AuthorSignature)
+	}
+	return telegramUserName(m.From), b.allowed(m.From)
+}
+
+// telegram messages are limited to 4096 characters
+const maxTelegramMessage = 4000
+
+// truncateTelegram shortens a reply to the telegram limit without splitting a multi-byte character.
+func truncateTelegram(reply string) string {
+	if len(reply) <= maxTelegramMessage {
+		return reply
+	}
+	cut := maxTelegramMessage
+	for cut > 0 && !utf8.RuneStart(reply[cut]) {
+		cut--
+	}
+	return reply[:cut] + "\n…"
+}
+
+// runTelegramBot polls Telegram for commands until the context is cancelled.
+func (c *Config) runTelegramBot(ctx context.Context) {
+	settings := c.telegramBotSettings()
+	var bot *tgbotapi.BotAPI
+	for {
+		var err error
+		if bot, err = tgbotapi.NewBotAPI(settings.ApiKey); err == nil {
+			break
+		}
+		l(slog.LevelWarn, "telegram bot:", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Minute):
+		}
+	}
+	l(slog.LevelInfo, fmt.Sprintf("🤖 telegram bot %s is listening for commands", bot.Self.UserName))
+
+	u := tgbotapi.NewUpdate(0)
+	u.Timeout = 60
+	updates := bot.GetUpdatesChan(u)
+	for {
+		select {
+		case <-ctx.Done():
+			bot.StopReceivingUpdates()
+			return
+		case update := <-updates:
+			m := update.Message
+			if m == nil {
+				m = update.ChannelPost
+			}
+			if m == nil || !m.IsCommand() || !settings.isChat(m.Chat) {
+				continue
+			}
+			var reply string
+			if user, ok := settings.sender(m); ok {
+				reply = c.telegramCommand(user, m.Command(), m.CommandArguments())
+			} else {
+				l(slog.LevelWarn, fmt.Sprintf("🤖 ignoring telegram command %s from %s who is not in allowed_users or allowed_signatures", m.Command(), user))
+				reply = "⛔ you are not allowed to use this bot"
+			}
+			mc := tgbotapi.NewMessage(m.Chat.ID, truncateTelegram(reply))
+			mc.ReplyToMessageID = m.MessageID
+			if _, err := bot.Send(mc); err != nil {
+				l(slog.LevelWarn, "telegram bot send:", err)
+			}
+		}
+	}
+}
+
+func telegramUserName(user *tgbotapi.User) string {
+	switch {
+	case user == nil:
+		return "unknown"
+	case user.UserName != "":
+		return "@" + user.UserName
+	default:
+		return fmt.Sprintf("%s (%d)", user.FirstName, user.ID)
+	}
+}
+
+const telegramHelp = `/status - summary of each chain
+/alerts - active alerts and their IDs
+/ack <id> [chain] - stop reminders and escalations for an alert, on every chain unless one is given
+/silence <chain> <duration> - silence a chain, for example /silence osmosis 2h`
+
+// telegramCommand runs a bot command and returns the reply.
+func (c *Config) telegramCommand(user, command, args string) string {
+	fields := strings.Fields(args)
+	switch command {
+	case "status":
+		return c.telegramStatus()
+	case "alerts":
+		return telegramAlerts()
+	case "ack":
+		if len(fields) != 1 && len(fields) != 2 {
+			return "usage: /ack <id> [chain], the IDs are listed by /alerts"
+		}
+		chain := ""
+		if len(fields) == 2 {
+			var ok bool
+			if chain, ok = c.findChain(fields[1]); !ok {
+				return "unknown chain " + fields[1]
+			}
+		}
+		reply, err := c.alertAction(actionAck, chain, fields[0], user)
+		if err != nil {
+			return err.Error()
+		}
+		return reply
+	case "silence":
+		if len(fields) != 2 {
+			return "usage: /silence <chain> <duration>, for example /silence osmosis 2h"
+		}
+		chain, ok := c.findChain(fields[0])
+		if !ok {
+			return "unknown chain " + fields[0]
+		}
+		s := &Silence{Chain: chain, Duration: fields[1], Comment: "telegram: " + user}
+		if err := silences.add(s); err != nil {
+			return "could not add silence: " + err.Error()
+		}
+		l(slog.LevelInfo, fmt.Sprintf("🔕 silence %s added by %s for %s until %s", s.ID, user, chain, s.End.Format(time.RFC3339)))
+		return fmt.Sprintf("🔕 %s silenced until %s (id %s)", chain, s.End.UTC().Format(time.RFC3339), s.ID)
+	case "help", "start":
+		return telegramHelp
+	default:
+		return "unknown command /" + command + "\n" + telegramHelp
+	}
+}
+
+// findChain looks up a chain by its name in the config file, case-insensitive, or by its chain-id.
+func (c *Config) findChain(nameOrId string) (string, bool) {
+	c.chainsMux.RLock()
+	defer c.chainsMux.RUnlock()
+	for name, cc := range c.Chains {
+		if strings.EqualFold(name, nameOrId) || cc.ChainId == nameOrId {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func (c *Config) telegramStatus() string {
+	c.chainsMux.RLock()
+	defer c.chainsMux.RUnlock()
+	names := make([]string, 0, len(c.Chains))
+	for name := range c.Chains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		cc := c.Chains[name]
+		healthy := 0
+		for _, node := range cc.Nodes {
+			if !node.down {
+				healthy += 1
+			}
+		}
+		active := alarms.getCount(name)
+		icon, moniker, state := "🟢", "", "bonded"
+		if vi := cc.valInfo; vi != nil {
+			moniker = vi.Moniker
+			switch {
+			case vi.Tombstoned:
+				icon, state = "🔴", "tombstoned"
+			case vi.Jailed:
+				icon, state = "🔴", "jailed"
+			case !vi.Bonded:
+				icon, state = "⚪", "not in the active set"
+			}
+		}
+		if icon == "🟢" && (active > 0 || cc.noNodes) {
+			icon = "🟠"
+		}
+		missed := "unknown"
+		if cc.valInfo != nil && cc.valInfo.Window > 0 {
+			missed = fmt.Sprintf("%d/%d", cc.valInfo.Missed, cc.valInfo.Window)
+		}
+		lines = append(lines, fmt.Sprintf("%s %s (%s) %s: %s\n    height %d, missed %s, %d/%d nodes up, %d active alerts",
+			icon, name, cc.ChainId, moniker, state, cc.lastBlockNum, missed, healthy, len(cc.Nodes), active))
+	}
+	if len(lines) == 0 {
+		return "no chains are configured"
+	}
+	return strings.Join(lines, "\n")
+}
+
+func telegramAlerts() string {
+	alarms.notifyMux.RLock()
+	defer alarms.notifyMux.RUnlock()
+	chains := make([]string, 0, len(alarms.AllAlarms))
+	for chain := range alarms.AllAlarms {
+		chains = append(chains, chain)
+	}
+	sort.Strings(chains)
+
+	lines := make([]string, 0)
+	for _, chain := range chains {
+		ids := make([]string, 0, len(alarms.AllAlarms[chain]))
+		for id := range alarms.AllAlarms[chain] {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			cache := alarms.AllAlarms[chain][id]
+			line := fmt.Sprintf("🚨 %s: %s\n    id %s, since %s", chain, cache.Message, id, cache.SentTime.UTC().Format(time.RFC3339))
+			if cache.AckedBy != "" {
+				line += ", acknowledged by " + cache.AckedBy
+			}
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return "✅ no active alerts"
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,193 @@
+package tenderduty
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func setupTelegramBotTest(t *testing.T) {
+	originalTd, originalSilences, originalRules := td, silences, repeatRules
+	td = createTestConfig()
+	td.ctx, td.cancel = context.WithCancel(context.Background())
+	td.Chains["test-chain"].valInfo = &ValInfo{Moniker: "testval", Bonded: true, Missed: 3, Window: 100}
+	td.Chains["test-chain"].lastBlockNum = 1234
+	td.Chains["test-chain"].Nodes = []*NodeConfig{{Url: "tcp://a"}, {Url: "tcp://b", down: true}}
+	newTestAlarmCache(t).AllAlarms = map[string]map[string]alertMsgCache{
+		"test-chain": {"ValidatorInactive_testval123": {Message: "testval is jailed", Severity: "critical", SentTime: time.Now()}},
+	}
+	silences = newSilenceStore()
+	repeatRules = []RepeatRule{{AlertType: "ValidatorInactive", RepeatInterval: time.Minute}}
+	t.Cleanup(func() {
+		td.cancel()
+		td, silences, repeatRules = originalTd, originalSilences, originalRules
+	})
+}
+
+func TestTelegramBotSettings(t *testing.T) {
+	c := &Config{
+		TelegramBot:        TelegramBotConfig{Enabled: true, AllowedUsers: []int64{42}},
+		DefaultAlertConfig: AlertConfig{Telegram: TeleConfig{ApiKey: "key", Channel: "-1001234"}},
+	}
+	bot := c.telegramBotSettings()
+	if bot.ApiKey != "key" || bot.Channel != "-1001234" {
+		t.Errorf("expected the default telegram settings, got %+v", bot)
+	}
+	if !bot.isChat(&tgbotapi.Chat{ID: -1001234}) || bot.isChat(&tgbotapi.Chat{ID: 42}) {
+		t.Error("numeric channel should match the chat ID")
+	}
+	bot.Channel = "@validator_ops"
+	if !bot.isChat(&tgbotapi.Chat{ID: 1, UserName: "Validator_Ops"}) || bot.isChat(&tgbotapi.Chat{ID: 1}) {
+		t.Error("channel name should match the chat username")
+	}
+	if !bot.allowed(&tgbotapi.User{ID: 42}) || bot.allowed(&tgbotapi.User{ID: 43}) || bot.allowed(nil) {
+		t.Error("only allow-listed users should be allowed")
+	}
+
+	fatal, problems := validateConfig(&Config{TelegramBot: TelegramBotConfig{Enabled: true}})
+	if !fatal || !strings.Contains(strings.Join(problems, "\n"), "allowed_users") {
+		t.Errorf("bot without allowed users should be fatal, got %v", problems)
+	}
+}
+
+func TestTelegramBotSender(t *testing.T) {
+	bot := TelegramBotConfig{AllowedUsers: []int64{42}, AllowedSignatures: []string{"Alice"}}
+	group := &tgbotapi.Chat{ID: -100, Type: "supergroup"}
+	channel := &tgbotapi.Chat{ID: -200, Type: "channel"}
+
+	tests := []struct {
+		name    string
+		msg     *tgbotapi.Message
+		user    string
+		allowed bool
+	}{
+		{"allowed user", &tgbotapi.Message{Chat: group, From: &tgbotapi.User{ID: 42, UserName: "ops"}}, "@ops", true},
+		{"other user", &tgbotapi.Message{Chat: group, From: &tgbotapi.User{ID: 43, UserName: "eve"}}, "@eve", false},
+		{"user with an allowed signature", &tgbotapi.Message{Chat: group, From: &tgbotapi.User{ID: 43, UserName: "eve"}, AuthorSignature: "Alice"}, "@eve", false},
+		{"signed channel post", &tgbotapi.Message{Chat: channel, SenderChat: channel, AuthorSignature: "Alice"}, "Alice", true},
+		{"other signature", &tgbotapi.Message{Chat: channel, SenderChat: channel, AuthorSignature: "Mallory"}, "Mallory", false},
+		{"unsigned channel post", &tgbotapi.Message{Chat: channel, SenderChat: channel}, "unsigned channel post", false},
+		{"post forwarded from another chat", &tgbotapi.Message{Chat: group, SenderChat: channel, AuthorSignature: "Alice"}, "unknown", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, allowed := bot.sender(tt.msg)
+			if user != tt.user || allowed != tt.allowed {
+				t.Errorf("sender() = %q %v, want %q %v", user, allowed, tt.user, tt.allowed)
+			}
+		})
+	}
+
+	fatal, problems := validateConfig(&Config{TelegramBot: TelegramBotConfig{Enabled: true, AllowedSignatures: []string{"Alice"}}})
+	if strings.Contains(strings.Join(problems, "\n"), "allowed_users") {
+		t.Errorf("bot with allowed signatures should not need allowed users, got %v %v", fatal, problems)
+	}
+}
+
+func TestTruncateTelegram(t *testing.T) {
+	if got := truncateTelegram("short"); got != "short" {
+		t.Errorf("short reply should not change, got %q", got)
+	}
+	// a four byte emoji straddles the limit
+	reply := strings.Repeat("a", maxTelegramMessage-2) + strings.Repeat("🚨", 10)
+	got := truncateTelegram(reply)
+	if !utf8.ValidString(got) {
+		t.Errorf("truncated reply is not valid UTF-8: %q", got[len(got)-10:])
+	}
+	if want := strings.Repeat("a", maxTelegramMessage-2) + "\n…"; got != want {
+		t.Errorf("unexpected truncation %q", got[maxTelegramMessage-10:])
+	}
+}
+
+func TestTelegramStatusAndAlerts(t *testing.T) {
+	setupTelegramBotTest(t)
+
+	status := td.telegramCommand("@ops", "status", "")
+	for _, want := range []string{"🟠 test-chain (test-chain-1) testval: bonded", "height 1234", "missed 3/100", "1/2 nodes up", "1 active alerts"} {
+		if !strings.Contains(status, want) {
+			t.Errorf("status should contain %q, got %q", want, status)
+		}
+	}
+
+	list := td.telegramCommand("@ops", "alerts", "")
+	if !strings.Contains(list, "testval is jailed") || !strings.Contains(list, "id ValidatorInactive_testval123") {
+		t.Errorf("unexpected alerts %q", list)
+	}
+	alarms.AllAlarms = map[string]map[string]alertMsgCache{}
+	if list = td.telegramCommand("@ops", "alerts", ""); !strings.Contains(list, "no active alerts") {
+		t.Errorf("unexpected alerts %q", list)
+	}
+}
+
+func TestTelegramAck(t *testing.T) {
+	setupTelegramBotTest(t)
+	id := "ValidatorInactive_testval123"
+
+	if reply := td.telegramCommand("@ops", "ack", "Unknown_id"); !strings.Contains(reply, "no longer active") {
+		t.Errorf("unexpected reply %q", reply)
+	}
+	if reply := td.telegramCommand("@ops", "ack", id+" nope"); !strings.Contains(reply, "unknown chain") {
+		t.Errorf("unexpected reply %q", reply)
+	}
+	if reply := td.telegramCommand("@ops", "ack", id+" test-chain-1"); !strings.Contains(reply, "acknowledged by @ops on test-chain") {
+		t.Errorf("unexpected reply %q", reply)
+	}
+	if got := alarms.AllAlarms["test-chain"][id].AckedBy; got != "@ops" {
+		t.Errorf("alert should be acknowledged by @ops, got %q", got)
+	}
+	if reply := td.telegramCommand("@ops", "alerts", ""); !strings.Contains(reply, "acknowledged by @ops") {
+		t.Errorf("alerts should show the acknowledgement, got %q", reply)
+	}
+
+	// acknowledged alerts are not repeated
+	td.remind()
+	if len(td.alertChan) != 0 {
+		t.Error("acknowledged alert should not be repeated")
+	}
+
+	// the acknowledgement survives the alert being raised again, and is gone once it resolves
+	td.alert("test-chain", "testval is jailed", "critical", false, &id, alertDetails{})
+	<-td.alertChan
+	if alarms.AllAlarms["test-chain"][id].AckedBy == "" {
+		t.Error("acknowledgement should be kept while the alert is active")
+	}
+	td.alert("test-chain", "testval is jailed", "critical", true, &id, alertDetails{})
+	<-td.alertChan
+	td.alert("test-chain", "testval is jailed", "critical", false, &id, alertDetails{})
+	<-td.alertChan
+	if alarms.AllAlarms["test-chain"][id].AckedBy != "" {
+		t.Error("a new occurrence of the alert should not be acknowledged")
+	}
+}
+
+func TestTelegramSilence(t *testing.T) {
+	setupTelegramBotTest(t)
+
+	if reply := td.telegramCommand("@ops", "silence", "test-chain"); !strings.HasPrefix(reply, "usage") {
+		t.Errorf("unexpected reply %q", reply)
+	}
+	if reply := td.telegramCommand("@ops", "silence", "nope 2h"); !strings.Contains(reply, "unknown chain") {
+		t.Errorf("unexpected reply %q", reply)
+	}
+	if reply := td.telegramCommand("@ops", "silence", "test-chain soon"); !strings.Contains(reply, "invalid duration") {
+		t.Errorf("unexpected reply %q", reply)
+	}
+	if reply := td.telegramCommand("@ops", "silence", "test-chain-1 2h"); !strings.Contains(reply, "test-chain silenced until") {
+		t.Errorf("unexpected reply %q", reply)
+	}
+	list := silences.list()
+	if len(list) != 1 || list[0].Chain != "test-chain" || list[0].Comment != "telegram: @ops" {
+		t.Fatalf("unexpected silences %+v", list)
+	}
+	if d := time.Until(list[0].End); d < time.Hour+59*time.Minute || d > 2*time.Hour {
+		t.Errorf("silence should last 2h, ends in %s", d)
+	}
+
+	if reply := td.telegramCommand("@ops", "reboot", ""); !strings.Contains(reply, "unknown command") {
+		t.Errorf("unexpected reply %q", reply)
+	}
+}
@@ -0,0 +1,141 @@
+package tenderduty
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/firstset/tenderduty/v2/td2/utils"
+)
+
+// defaultTemplate is used for the destinations and alert types that have no template, and applies to all of them.
+const defaultTemplate = "default"
+
+// AlertTemplateData is what a message template is rendered with, see the Message Templates section in docs/config.md.
+type AlertTemplateData struct {
+	// Chain is the name of the chain in the config file
+	Chain     string
+	ChainID   string
+	ChainName string
+	Moniker   string
+	Valoper   string
+	Valcons   string
+	Severity  string
+	Resolved  bool
+	// AlertType is the first part of the alert ID, for example ConsecutiveBlocksMissed
+	AlertType string
+	// Labels tell apart alerts of the same type, for example the RPC node or the proposal
+	Labels map[string]string
+	ID     string
+	// Message is the built-in alert text
+	Message   string
+	Value     float64
+	Threshold float64
+}
+
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"human": utils.HumanSI,
+}
+
+// messageTemplates are the compiled templates from the config file, by destination and then alert type.
+var messageTemplates map[string]map[string]*template.Template
+
+// compileTemplates parses the templates from the config file.
+func compileTemplates(templates map[string]map[string]string) (map[string]map[string]*template.Template, error) {
+	compiled := make(map[string]map[string]*template.Template)
+	for dest, byType := range templates {
+		compiled[dest] = make(map[string]*template.Template)
+		for alertType, text := range byType {
+			t, err := template.New(dest + "/" + alertType).Funcs(templateFuncs).Parse(text)
+			if err != nil {
+				return nil, err
+			}
+			compiled[dest][alertType] = t
+		}
+	}
+	return compiled, nil
+}
+
+// validateTemplates checks that the templates parse, are for known destinations, and render with sample data.
+func validateTemplates(templates map[string]map[string]string) (fatal bool, problems []string) {
+	compiled, err := compileTemplates(templates)
+	if err != nil {
+		return true, []string{"error: could not parse message template: " + err.Error()}
+	}
+	dests := make([]string, 0, len(compiled))
+	for dest := range compiled {
+		dests = append(dests, dest)
+	}
+	sort.Strings(dests)
+	sample := AlertTemplateData{Chain: "chain", ChainID: "chain-1", Moniker: "moniker", Severity: "critical", AlertType: "ValidatorInactive", Message: "message"}
+	for _, dest := range dests {
+		if _, ok := getNotifier(dest); dest != defaultTemplate && !ok {
+			fatal = true
+			problems = append(problems, fmt.Sprintf("error: message templates for unknown destination %s", dest))
+			continue
+		}
+		for alertType, t := range compiled[dest] {
+			if e := t.Execute(&bytes.Buffer{}, sample); e != nil {
+				fatal = true
+				problems = append(problems, fmt.Sprintf("error: message template %s for %s: %s", alertType, dest, e))
+			}
+		}
+	}
+	return
+}
+
+// templateFor finds the template for a destination and alert type, trying the destination's template for the alert
+// type, then its default, then the default templates. It returns nil when the built-in message should be used.
+func templateFor(destination, alertType string) *template.Template {
+	for _, dest := range []string{destination, defaultTemplate} {
+		byType := messageTemplates[dest]
+		if t := byType[alertType]; t != nil {
+			return t
+		}
+		if t := byType[defaultTemplate]; t != nil {
+			return t
+		}
+	}
+	return nil
+}
+
+func (a *alertMsg) templateData() AlertTemplateData {
+	return AlertTemplateData{
+		Chain:     a.configName,
+		ChainID:   a.chainId,
+		ChainName: a.chainName,
+		Moniker:   a.moniker,
+		Valoper:   a.valoperAddress,
+		Valcons:   a.valconsAddress,
+		Severity:  a.severity,
+		Resolved:  a.resolved,
+		AlertType: string(a.kind),
+		Labels:    a.labels,
+		ID:        a.uniqueId,
+		Message:   a.message,
+		Value:     a.value,
+		Threshold: a.threshold,
+	}
+}
+
+// templated returns the alert with its message rendered for a destination. The alert is returned as is when there is
+// no template, or when the template fails, so an alert is never lost to a template mistake.
+func (a *alertMsg) templated(destination string) *alertMsg {
+	t := templateFor(baseNotifierName(destination), string(a.kind))
+	if t == nil {
+		return a
+	}
+	buf := &bytes.Buffer{}
+	if err := t.Execute(buf, a.templateData()); err != nil {
+		l(slog.LevelWarn, fmt.Sprintf("message template %s failed, sending the built-in message: %s", t.Name(), err))
+		return a
+	}
+	msg := *a
+	msg.message = strings.TrimSpace(buf.String())
+	return &msg
+}
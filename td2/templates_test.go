@@ -0,0 +1,95 @@
+package tenderduty
+
+import (
+	"strings"
+	"testing"
+)
+
+func setTemplates(t *testing.T, templates map[string]map[string]string) {
+	original := messageTemplates
+	compiled, err := compileTemplates(templates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	messageTemplates = compiled
+	t.Cleanup(func() { messageTemplates = original })
+}
+
+func testTemplateMsg() *alertMsg {
+	return &alertMsg{
+		configName:     "osmosis",
+		chainId:        "osmosis-1",
+		moniker:        "testval",
+		valoperAddress: "osmovaloper1abc",
+		severity:       "critical",
+		uniqueId:       "ConsecutiveBlocksMissed_osmovaloper1abc",
+		message:        "testval has missed 12 blocks on osmosis-1",
+		alertDetails:   alertDetails{kind: kindConsecutiveBlocksMissed, value: 12, threshold: 5},
+	}
+}
+
+func TestTemplatedDefaultsToBuiltInMessage(t *testing.T) {
+	setTemplates(t, nil)
+	msg := testTemplateMsg()
+	if got := msg.templated("discord"); got != msg {
+		t.Error("without templates the alert should be sent as is")
+	}
+}
+
+func TestTemplatedLookupOrder(t *testing.T) {
+	setTemplates(t, map[string]map[string]string{
+		"default": {
+			"default":                 "default {{.Message}}",
+			"ConsecutiveBlocksMissed": "{{.Moniker}} missed {{.Value}}/{{.Threshold}} on {{.ChainID}}",
+		},
+		"pagerduty": {
+			"default": "{{upper .Severity}}: {{.Message}} https://runbooks.internal/{{.AlertType}}",
+		},
+		"slack": {
+			"ConsecutiveBlocksMissed": "{{if .Resolved}}ok{{else}}{{.Chain}} {{.Valoper}}{{end}}",
+		},
+	})
+	msg := testTemplateMsg()
+
+	for dest, want := range map[string]string{
+		"slack":                  "osmosis osmovaloper1abc",
+		"pagerduty":              "CRITICAL: testval has missed 12 blocks on osmosis-1 https://runbooks.internal/ConsecutiveBlocksMissed",
+		"pagerduty@escalation-1": "CRITICAL: testval has missed 12 blocks on osmosis-1 https://runbooks.internal/ConsecutiveBlocksMissed",
+		"discord":                "testval missed 12/5 on osmosis-1",
+	} {
+		if got := msg.templated(dest).message; got != want {
+			t.Errorf("%s: expected %q, got %q", dest, want, got)
+		}
+	}
+	if msg.message != "testval has missed 12 blocks on osmosis-1" {
+		t.Error("the original alert should not be changed")
+	}
+
+	msg.kind = kindValidatorInactive
+	if got := msg.templated("discord").message; got != "default testval has missed 12 blocks on osmosis-1" {
+		t.Errorf("expected the default template, got %q", got)
+	}
+}
+
+func TestTemplatedFallsBackOnError(t *testing.T) {
+	setTemplates(t, map[string]map[string]string{"default": {"default": "{{.Missing}}"}})
+	msg := testTemplateMsg()
+	if got := msg.templated("discord"); got.message != msg.message {
+		t.Errorf("a failing template should send the built-in message, got %q", got.message)
+	}
+}
+
+func TestValidateTemplates(t *testing.T) {
+	if fatal, problems := validateTemplates(map[string]map[string]string{"default": {"default": "{{.Message}} {{human .Value}}"}}); fatal || len(problems) > 0 {
+		t.Errorf("valid template should pass, got %v", problems)
+	}
+	for name, templates := range map[string]map[string]map[string]string{
+		"parse error":         {"default": {"default": "{{.Message"}},
+		"unknown field":       {"slack": {"default": "{{.Hostname}}"}},
+		"unknown destination": {"carrier-pigeon": {"default": "{{.Message}}"}},
+	} {
+		if fatal, problems := validateTemplates(templates); !fatal || !strings.HasPrefix(strings.Join(problems, ""), "error:") {
+			t.Errorf("%s should be fatal, got %v", name, problems)
+		}
+	}
+}
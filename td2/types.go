@@ -66,6 +66,21 @@ func applyAlertDefaults(dst, src any) {
 		switch df.Kind() {
 		case reflect.Struct:
 			applyAlertDefaults(df.Addr().Interface(), sf.Addr().Interface())
+		case reflect.Map:
+			// maps are merged, the keys that are not set come from the defaults
+			if sf.IsNil() {
+				continue
+			}
+			if df.IsNil() {
+				// copied so that merging more defaults later does not change the source
+				df.Set(reflect.MakeMapWithSize(sf.Type(), sf.Len()))
+			}
+			iter := sf.MapRange()
+			for iter.Next() {
+				if !df.MapIndex(iter.Key()).IsValid() {
+					df.SetMapIndex(iter.Key(), iter.Value())
+				}
+			}
 		case reflect.Pointer:
 			if df.IsNil() {
 				df.Set(sf)
@@ -124,6 +139,9 @@ type Config struct {
 	// HideLogs controls whether logs are sent to the dashboard. It will also suppress many alarm details.
 	// This is useful if the dashboard will be public.
 	HideLogs bool `yaml:"hide_logs"`
+	// DashboardAPIToken is the bearer token required by dashboard API endpoints that change state, such as adding
+	// a silence. These endpoints are disabled when it is empty.
+	DashboardAPIToken string `yaml:"dashboard_api_token"`
 
 	// NodeDownMin controls how long we wait before sending an alert that a node is not responding or has
 	// fallen behind.
@@ -144,6 +162,30 @@ type Config struct {
 	DefaultAlertConfig AlertConfig `yaml:"default_alert_config"`
 	// Healthcheck information
 	Healthcheck HealthcheckConfig `yaml:"healthcheck"`
+	// Silences mute notifications for matching alerts during a maintenance window
+	Silences []*Silence `yaml:"silences"`
+	// InhibitRules override the default rules that hold back dependent alerts while a parent alert is active
+	InhibitRules []InhibitRule `yaml:"inhibit_rules"`
+	// RepeatRules send reminders for alerts that are still active, per alert type and destination
+	RepeatRules []RepeatRule `yaml:"repeat_rules"`
+	// Routes select the destinations of alerts by type, chain, severity and chain labels
+	Routes []RouteRule `yaml:"routes"`
+	// Templates are text/template message templates by destination and then alert type, "default" applies to all
+	Templates map[string]map[string]string `yaml:"templates"`
+	// Outbox controls how failed notifications are retried
+	Outbox OutboxConfig `yaml:"outbox"`
+	// History keeps a record of every alert that fired or resolved, and of its deliveries
+	History HistoryConfig `yaml:"alert_history"`
+	// Digests send a summary of the chains on a schedule
+	Digests []DigestConfig `yaml:"digests"`
+	// Grouping merges alerts that arrive together into one message per destination
+	Grouping GroupingConfig `yaml:"grouping"`
+	// Flapping holds back the notifications of alerts that fire and resolve too often
+	Flapping FlapConfig `yaml:"flapping"`
+	// Bootstrap fills the dashboard's block grid from the recent blocks at startup
+	Bootstrap BootstrapConfig `yaml:"bootstrap"`
+	// TelegramBot answers commands from allow-listed users in the Telegram channel
+	TelegramBot TelegramBotConfig `yaml:"telegram_bot"`
 
 	// When GovernanceAlerts is true, GovernanceAlertsReminderInterval defines how often to remind the user about unvoted proposals, every 6 hours by default
 	GovernanceAlertsReminderInterval int `yaml:"governance_alerts_reminder_interval"`
@@ -162,6 +204,8 @@ type savedState struct {
 	Alarms    *alarmCache                     `json:"alarms"`
 	Blocks    map[string][]int                `json:"blocks"`
 	NodesDown map[string]map[string]time.Time `json:"nodes_down"`
+	Silences  []*Silence                      `json:"silences"`
+	Outbox    *outboxState                    `json:"outbox"`
 }
 
 type ProviderConfig struct {
@@ -173,7 +217,6 @@ type ProviderConfig struct {
 // validators can be monitored on a single chain.
 type ChainConfig struct {
 	name                string
-	wsclient            *TmConn                   // custom websocket client to work around wss:// bugs in tendermint
 	client              *rpchttp.HTTP             // legit tendermint client
 	noNodes             bool                      // tracks if all nodes are down
 	valInfo             *ValInfo                  // recent validator state, only refreshed every few minutes
@@ -205,6 +248,9 @@ type ChainConfig struct {
 	statTotalPropsEmpty  float64
 	statConsecutiveEmpty float64
 
+	statTotalMissedProps       float64
+	statConsecutiveMissedProps float64
+
 	// ChainId is used to ensure any endpoints contacted claim to be on the correct chain. This is a weak verification,
 	// no light client validation is performed, so caution is advised when using public endpoints.
 	ChainId string `yaml:"chain_id"`
@@ -224,6 +270,8 @@ type ChainConfig struct {
 	PublicFallback bool `yaml:"public_fallback"`
 	// Nodes defines what RPC servers to connect to.
 	Nodes []*NodeConfig `yaml:"nodes"`
+	// WebsocketNodes is how many healthy nodes are subscribed to for blocks and votes at once, 2 by default
+	WebsocketNodes int `yaml:"websocket_nodes"`
 	// Provider defines what implementation should be used for checking a chain's status
 	// currently it supports two values: `default` or `namada`
 	Provider ProviderConfig `yaml:"provider"`
@@ -235,6 +283,8 @@ type ChainConfig struct {
 	// If not set, defaults to lowercase of the chain's display name.
 	// Used to fetch chain params from cosmos.directory and as RPC fallback.
 	ChainName string `yaml:"chain_name"`
+	// Labels are matched by the chain_labels of routes, for example team: infra
+	Labels map[string]string `yaml:"labels"`
 }
 
 // mkUpdate returns the info needed by prometheus for a gauge.
@@ -279,6 +329,14 @@ type AlertConfig struct {
 	// Whether to alert on consecutive empty blocks
 	ConsecutiveEmptyAlerts *bool `yaml:"consecutive_empty_enabled"`
 
+	// How many proposals in a row the validator can miss before alerting, a missed proposal is a height it was the
+	// round 0 proposer for but another validator's block was committed
+	MissedProposals *int `yaml:"missed_proposals"`
+	// Tag for pagerduty to set the alert priority for missed proposals
+	MissedProposalsPriority string `yaml:"missed_proposals_priority"`
+	// Whether to alert on missed proposals
+	MissedProposalsAlerts *bool `yaml:"missed_proposals_enabled"`
+
 	// EmptyWindow is how many blocks empty as a percentage of proposed blocks since tenderduty was started to trigger an alert
 	EmptyWindow *int `yaml:"empty_percentage"`
 	// EmptyPercentagePriority is a tag for pagerduty to route on priority
@@ -303,6 +361,18 @@ type AlertConfig struct {
 	UnclaimedRewardsAlerts    *bool    `yaml:"unclaimed_rewards_alerts"`
 	UnclaimedRewardsThreshold *float64 `yaml:"unclaimed_rewards_threshold_in_fiat_currency"`
 
+	// Whether to alert when the validator double signs, on unless it is set to no
+	DoubleSignAlerts *bool `yaml:"double_sign_alerts"`
+	// Whether to send an info alert when a block has evidence against another validator
+	EvidenceAlerts *bool `yaml:"evidence_alerts"`
+
+	// Severities overrides the severity of an alert type, for example StakeChange: critical
+	Severities map[string]string `yaml:"severities"`
+	// For is how long the condition of an alert type must hold before it fires, for example PercentageBlocksMissed: 5m
+	For map[string]time.Duration `yaml:"for"`
+	// ResolveFor is how long the condition of an alert type must be clear before it resolves
+	ResolveFor map[string]time.Duration `yaml:"resolve_for"`
+
 	// chain specific overrides for alert destinations.
 	// Pagerduty configuration values
 	Pagerduty PDConfig `yaml:"pagerduty"`
@@ -314,6 +384,21 @@ type AlertConfig struct {
 	Slack SlackConfig `yaml:"slack"`
 	// Generic webhook information
 	Webhook WebhookConfig `yaml:"webhook"`
+	// Email (SMTP) information
+	Email EmailConfig `yaml:"email"`
+	// Opsgenie Alert API information
+	Opsgenie OpsgenieConfig `yaml:"opsgenie"`
+	// Prometheus Alertmanager information
+	Alertmanager AlertmanagerConfig `yaml:"alertmanager"`
+	// Matrix room information
+	Matrix MatrixConfig `yaml:"matrix"`
+	// ntfy topic information
+	Ntfy NtfyConfig `yaml:"ntfy"`
+	// Gotify server information
+	Gotify GotifyConfig `yaml:"gotify"`
+
+	// Escalation notifies more destinations in stages while an alert stays unresolved
+	Escalation EscalationConfig `yaml:"escalation"`
 }
 
 // NodeConfig holds the basic information for a node to connect to.
@@ -334,6 +419,9 @@ type PDConfig struct {
 	ApiKey            string `yaml:"api_key"`
 	DefaultSeverity   string `yaml:"default_severity"`
 	SeverityThreshold string `yaml:"severity_threshold"`
+	// Priorities are sent as the priority in the custom details of an alert type's events, for example
+	// StakeChange: P1, so that an event orchestration rule can set the incident priority
+	Priorities map[string]string `yaml:"priorities"`
 }
 
 // DiscordConfig holds the information needed to publish to a Discord webhook for sending alerts
@@ -342,6 +430,10 @@ type DiscordConfig struct {
 	Webhook           string   `yaml:"webhook"`
 	Mentions          []string `yaml:"mentions"`
 	SeverityThreshold string   `yaml:"severity_threshold"`
+	// Interactive adds Acknowledge, Silence 1h and Resolve buttons, the webhook must belong to a Discord application
+	Interactive *bool `yaml:"interactive"`
+	// PublicKey is the application's public key, used to verify the button clicks
+	PublicKey string `yaml:"public_key"`
 }
 
 // TeleConfig holds the information needed to publish to a Telegram webhook for sending alerts
@@ -359,6 +451,10 @@ type SlackConfig struct {
 	Webhook           string   `yaml:"webhook"`
 	Mentions          []string `yaml:"mentions"`
 	SeverityThreshold string   `yaml:"severity_threshold"`
+	// Interactive adds Acknowledge, Silence 1h and Resolve buttons, the webhook must belong to a Slack app
+	Interactive *bool `yaml:"interactive"`
+	// SigningSecret is the Slack app's signing secret, used to verify the button clicks
+	SigningSecret string `yaml:"signing_secret"`
 }
 
 // WebhookConfig holds the information needed to send alerts to a generic webhook endpoint
@@ -370,6 +466,75 @@ type WebhookConfig struct {
 	DisableResolveMessage *bool  `yaml:"disable_resolve_message"`
 }
 
+// EmailConfig holds the information needed to send alerts by email through an SMTP server
+type EmailConfig struct {
+	Enabled *bool  `yaml:"enabled"`
+	Host    string `yaml:"host"`
+	Port    int    `yaml:"port"`
+	// TLS is one of starttls (the default), tls for implicit TLS, or none for a plain connection to a local relay
+	TLS               string   `yaml:"tls"`
+	Username          string   `yaml:"username"`
+	Password          string   `yaml:"password"`
+	From              string   `yaml:"from"`
+	To                []string `yaml:"to"`
+	SeverityThreshold string   `yaml:"severity_threshold"`
+}
+
+// OpsgenieConfig is the information required to create and close alerts with the Opsgenie Alert API
+type OpsgenieConfig struct {
+	Enabled *bool  `yaml:"enabled"`
+	ApiKey  string `yaml:"api_key"`
+	// ApiURL defaults to https://api.opsgenie.com, EU accounts should use https://api.eu.opsgenie.com
+	ApiURL            string   `yaml:"api_url"`
+	Tags              []string `yaml:"tags"`
+	SeverityThreshold string   `yaml:"severity_threshold"`
+}
+
+// AlertmanagerConfig holds the information needed to post alerts to a Prometheus Alertmanager
+type AlertmanagerConfig struct {
+	Enabled *bool `yaml:"enabled"`
+	// URL is the base URL of the Alertmanager, /api/v2/alerts is appended
+	URL      string `yaml:"url"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	// Labels are added to every alert, useful for routing in the Alertmanager
+	Labels            map[string]string `yaml:"labels"`
+	SeverityThreshold string            `yaml:"severity_threshold"`
+}
+
+// MatrixConfig holds the information needed to post alerts to a Matrix room
+type MatrixConfig struct {
+	Enabled *bool `yaml:"enabled"`
+	// Homeserver is the base URL of the homeserver, for example https://matrix.org
+	Homeserver        string   `yaml:"homeserver"`
+	AccessToken       string   `yaml:"access_token"`
+	RoomID            string   `yaml:"room_id"`
+	Mentions          []string `yaml:"mentions"`
+	SeverityThreshold string   `yaml:"severity_threshold"`
+}
+
+// NtfyConfig holds the information needed to publish alerts to a ntfy topic
+type NtfyConfig struct {
+	Enabled *bool `yaml:"enabled"`
+	// Server defaults to https://ntfy.sh
+	Server string `yaml:"server"`
+	Topic  string `yaml:"topic"`
+	// Token is an access token, alternatively Username and Password can be used for basic auth
+	Token             string `yaml:"token"`
+	Username          string `yaml:"username"`
+	Password          string `yaml:"password"`
+	SeverityThreshold string `yaml:"severity_threshold"`
+}
+
+// GotifyConfig holds the information needed to push alerts to a Gotify server
+type GotifyConfig struct {
+	Enabled *bool  `yaml:"enabled"`
+	Server  string `yaml:"server"`
+	// Token is the application token created in Gotify
+	Token             string `yaml:"token"`
+	SeverityThreshold string `yaml:"severity_threshold"`
+}
+
 // HealthcheckConfig holds the information needed to send pings to a healthcheck endpoint
 type HealthcheckConfig struct {
 	Enabled  bool          `yaml:"enabled"`
@@ -404,6 +569,179 @@ func validateConfig(c *Config) (fatal bool, problems []string) {
 		}
 	}
 
+	if boolVal(c.DefaultAlertConfig.Email.Enabled) {
+		if c.DefaultAlertConfig.Email.Host == "" || c.DefaultAlertConfig.Email.From == "" || len(c.DefaultAlertConfig.Email.To) == 0 {
+			fatal = true
+			problems = append(problems, "error: email alerts are enabled, but the host, from or to settings are missing.")
+		}
+		switch strings.ToLower(c.DefaultAlertConfig.Email.TLS) {
+		case "", "starttls", "tls", "none":
+		default:
+			fatal = true
+			problems = append(problems, fmt.Sprintf("error: the email tls setting %s is not valid, use starttls, tls, or none", c.DefaultAlertConfig.Email.TLS))
+		}
+	}
+
+	if boolVal(c.DefaultAlertConfig.Opsgenie.Enabled) && c.DefaultAlertConfig.Opsgenie.ApiKey == "" {
+		fatal = true
+		problems = append(problems, "error: opsgenie alerts are enabled, but no api_key is set.")
+	}
+
+	if boolVal(c.DefaultAlertConfig.Alertmanager.Enabled) {
+		if _, err = url.Parse(c.DefaultAlertConfig.Alertmanager.URL); err != nil || c.DefaultAlertConfig.Alertmanager.URL == "" {
+			fatal = true
+			problems = append(problems, fmt.Sprintf("error: the alertmanager URL %s does not appear to be valid", c.DefaultAlertConfig.Alertmanager.URL))
+		}
+	}
+
+	if boolVal(c.DefaultAlertConfig.Matrix.Enabled) && (c.DefaultAlertConfig.Matrix.Homeserver == "" || c.DefaultAlertConfig.Matrix.AccessToken == "") {
+		fatal = true
+		problems = append(problems, "error: matrix alerts are enabled, but the homeserver or access_token is not set.")
+	}
+
+	if boolVal(c.DefaultAlertConfig.Gotify.Enabled) && c.DefaultAlertConfig.Gotify.Server == "" {
+		fatal = true
+		problems = append(problems, "error: gotify alerts are enabled, but no server is set.")
+	}
+
+	if boolVal(c.DefaultAlertConfig.Slack.Interactive) && c.DefaultAlertConfig.Slack.SigningSecret == "" {
+		fatal = true
+		problems = append(problems, "error: slack buttons are enabled, but no signing_secret is set.")
+	}
+	if boolVal(c.DefaultAlertConfig.Discord.Interactive) {
+		if _, err = discordPublicKey(c.DefaultAlertConfig.Discord.PublicKey); err != nil {
+			fatal = true
+			problems = append(problems, fmt.Sprintf("error: discord buttons are enabled, but the public_key is not valid: %s", err))
+		}
+	}
+	if (boolVal(c.DefaultAlertConfig.Slack.Interactive) || boolVal(c.DefaultAlertConfig.Discord.Interactive)) && !c.EnableDash {
+		problems = append(problems, "warning: slack and discord buttons are handled by the dashboard server, which is not enabled")
+	}
+
+	for i, s := range c.Silences {
+		if err := s.validate(); err != nil {
+			fatal = true
+			problems = append(problems, fmt.Sprintf("error: silence %d (%s) is not valid: %s", i+1, s.ID, err))
+		}
+	}
+
+	for i, r := range c.InhibitRules {
+		if r.Source == "" {
+			fatal = true
+			problems = append(problems, fmt.Sprintf("error: inhibit rule %d does not have a source", i+1))
+		}
+	}
+
+	alertConfigs := map[string]*AlertConfig{"default_alert_config": &c.DefaultAlertConfig}
+	for name, cc := range c.Chains {
+		alertConfigs[name] = &cc.Alerts
+	}
+	for name, ac := range alertConfigs {
+		for alertType, severity := range ac.Severities {
+			if !slices.Contains(alertKinds, alertKind(alertType)) {
+				problems = append(problems, fmt.Sprintf("warning: %s has a severity for an unknown alert type %s", name, alertType))
+			}
+			if !slices.Contains([]string{"critical", "warning", "info"}, severity) {
+				fatal = true
+				problems = append(problems, fmt.Sprintf("error: %s has an unknown severity %s for %s", name, severity, alertType))
+			}
+		}
+		if f, p := validatePending(name, ac); len(p) > 0 {
+			fatal = fatal || f
+			problems = append(problems, p...)
+		}
+		for alertType := range ac.Pagerduty.Priorities {
+			if !slices.Contains(alertKinds, alertKind(alertType)) {
+				problems = append(problems, fmt.Sprintf("warning: %s has a pagerduty priority for an unknown alert type %s", name, alertType))
+			}
+		}
+	}
+
+	escalations := map[string]*EscalationConfig{"default_alert_config": &c.DefaultAlertConfig.Escalation}
+	for name, cc := range c.Chains {
+		escalations[name] = &cc.Alerts.Escalation
+	}
+	for name, e := range escalations {
+		if !boolVal(e.Enabled) {
+			continue
+		}
+		if len(e.Stages) == 0 {
+			problems = append(problems, fmt.Sprintf("warning: escalation is enabled for %s, but it has no stages", name))
+		}
+		for i, stage := range e.Stages {
+			if i > 0 && stage.After < e.Stages[i-1].After {
+				fatal = true
+				problems = append(problems, fmt.Sprintf("error: escalation stage %d for %s starts before the stage preceding it", i+1, name))
+			}
+			for _, dest := range stage.Destinations {
+				if _, ok := getNotifier(dest); !ok {
+					fatal = true
+					problems = append(problems, fmt.Sprintf("error: escalation stage %d for %s has an unknown destination %s", i+1, name, dest))
+				}
+			}
+		}
+	}
+
+	for i, r := range c.RepeatRules {
+		if r.RepeatInterval <= 0 {
+			fatal = true
+			problems = append(problems, fmt.Sprintf("error: repeat rule %d (%s) needs a repeat_interval greater than zero", i+1, r.AlertType))
+		}
+		if _, ok := getNotifier(r.Destination); r.Destination != "" && !ok {
+			fatal = true
+			problems = append(problems, fmt.Sprintf("error: repeat rule %d (%s) has an unknown destination %s", i+1, r.AlertType, r.Destination))
+		}
+		if r.MaxRepeats < 0 {
+			fatal = true
+			problems = append(problems, fmt.Sprintf("error: repeat rule %d (%s) has a negative max_repeats", i+1, r.AlertType))
+		}
+	}
+
+	if f, p := validateRoutes(c); len(p) > 0 {
+		fatal = fatal || f
+		problems = append(problems, p...)
+	}
+
+	if f, p := validateTemplates(c.Templates); len(p) > 0 {
+		fatal = fatal || f
+		problems = append(problems, p...)
+	}
+
+	if f, p := validateDigests(c); len(p) > 0 {
+		fatal = fatal || f
+		problems = append(problems, p...)
+	}
+
+	if f, p := validateGrouping(&c.Grouping); len(p) > 0 {
+		fatal = fatal || f
+		problems = append(problems, p...)
+	}
+
+	if f, p := validateFlapping(&c.Flapping); len(p) > 0 {
+		fatal = fatal || f
+		problems = append(problems, p...)
+	}
+
+	if c.Outbox.MaxAttempts < 0 || c.Outbox.InitialBackoff < 0 || c.Outbox.MaxBackoff < 0 {
+		fatal = true
+		problems = append(problems, "error: outbox settings can not be negative")
+	}
+	if c.Outbox.InitialBackoff > 0 && c.Outbox.MaxBackoff > 0 && c.Outbox.MaxBackoff < c.Outbox.InitialBackoff {
+		problems = append(problems, "warning: outbox max_backoff is shorter than initial_backoff, retries will wait max_backoff")
+	}
+
+	if c.TelegramBot.Enabled {
+		bot := c.telegramBotSettings()
+		if bot.ApiKey == "" || bot.Channel == "" {
+			fatal = true
+			problems = append(problems, "error: the telegram bot needs an api_key and channel, either in telegram_bot or in default_alert_config.telegram")
+		}
+		if len(bot.AllowedUsers) == 0 && len(bot.AllowedSignatures) == 0 {
+			fatal = true
+			problems = append(problems, "error: the telegram bot needs at least one user ID in allowed_users, or an author signature in allowed_signatures for a channel")
+		}
+	}
+
 	if c.NodeDownMin < 3 {
 		problems = append(problems, "warning: setting 'node_down_alert_minutes' to less than three minutes might result in false alarms")
 	}
@@ -431,6 +769,7 @@ func validateConfig(c *Config) (fatal bool, problems []string) {
 		v.valInfo = &ValInfo{Moniker: "not connected"}
 
 		applyAlertDefaults(&v.Alerts, &c.DefaultAlertConfig)
+		v.Alerts.Escalation.mergeStages(&v.Alerts)
 
 		if td.EnableDash {
 			td.updateChan <- &dash.ChainStatus{
@@ -609,13 +948,9 @@ func loadConfig(yamlFile, stateFile, chainConfigDirectory string, password *stri
 
 	// handle cached data. FIXME: incomplete.
 	c.alarms = &alarmCache{
-		SentPdAlarms:  make(map[string]alertMsgCache),
-		SentTgAlarms:  make(map[string]alertMsgCache),
-		SentDiAlarms:  make(map[string]alertMsgCache),
-		SentSlkAlarms: make(map[string]alertMsgCache),
-		SentWHAlarms:  make(map[string]alertMsgCache),
-		AllAlarms:     make(map[string]map[string]alertMsgCache),
-		notifyMux:     sync.RWMutex{},
+		Sent:      make(map[string]map[string]alertMsgCache),
+		AllAlarms: make(map[string]map[string]alertMsgCache),
+		notifyMux: sync.RWMutex{},
 	}
 
 	//#nosec -- variable specified on command line
@@ -641,34 +976,57 @@ func loadConfig(yamlFile, stateFile, chainConfigDirectory string, password *stri
 
 	// restore alarm state to prevent duplicate alerts
 	if saved.Alarms != nil {
-		if saved.Alarms.SentTgAlarms != nil {
-			alarms.SentTgAlarms = saved.Alarms.SentTgAlarms
-			clearStale(alarms.SentTgAlarms, "telegram", boolVal(c.DefaultAlertConfig.Pagerduty.Enabled), staleHours)
-		}
-		if saved.Alarms.SentPdAlarms != nil {
-			alarms.SentPdAlarms = saved.Alarms.SentPdAlarms
-			clearStale(alarms.SentPdAlarms, "PagerDuty", boolVal(c.DefaultAlertConfig.Pagerduty.Enabled), staleHours)
-		}
-		if saved.Alarms.SentDiAlarms != nil {
-			alarms.SentDiAlarms = saved.Alarms.SentDiAlarms
-			clearStale(alarms.SentDiAlarms, "Discord", boolVal(c.DefaultAlertConfig.Pagerduty.Enabled), staleHours)
-		}
-		if saved.Alarms.SentSlkAlarms != nil {
-			alarms.SentSlkAlarms = saved.Alarms.SentSlkAlarms
-			clearStale(alarms.SentSlkAlarms, "Slack", boolVal(c.DefaultAlertConfig.Pagerduty.Enabled), staleHours)
+		if saved.Alarms.Sent == nil {
+			// state written by an older release has a map per destination, migrate it to the notifier names.
+			legacy := &struct {
+				Alarms *legacyAlarmCache `json:"alarms"`
+			}{}
+			if json.Unmarshal(b, legacy) == nil && legacy.Alarms != nil {
+				saved.Alarms.Sent = legacy.Alarms.sent()
+			}
 		}
-		if saved.Alarms.SentWHAlarms != nil {
-			alarms.SentWHAlarms = saved.Alarms.SentWHAlarms
-			clearStale(alarms.SentWHAlarms, "Webhook", boolVal(c.DefaultAlertConfig.Webhook.Enabled), staleHours)
+		for name, sent := range saved.Alarms.Sent {
+			if sent == nil {
+				continue
+			}
+			n, ok := getNotifier(baseNotifierName(name))
+			if !ok {
+				l(slog.LevelWarn, "🗑 not restoring alarm state for unknown destination", name)
+				continue
+			}
+			alarms.Sent[name] = sent
+			clearStale(alarms.Sent[name], name, n.Enabled(&c.DefaultAlertConfig), staleHours)
 		}
 		if saved.Alarms.AllAlarms != nil {
 			alarms.AllAlarms = saved.Alarms.AllAlarms
 			for _, alrm := range saved.Alarms.AllAlarms {
-				clearStale(alrm, "dashboard", boolVal(c.DefaultAlertConfig.Pagerduty.Enabled), staleHours)
+				clearStale(alrm, "dashboard", false, staleHours)
 			}
 		}
 	}
 
+	inhibitRules = mergeInhibitRules(defaultInhibitRules, c.InhibitRules)
+	repeatRules = c.RepeatRules
+	routeRules = c.Routes
+	// a template that does not parse is reported by validateConfig
+	messageTemplates, _ = compileTemplates(c.Templates)
+
+	// notifications that were not delivered before exiting are retried
+	outbox.restore(c, saved.Outbox)
+
+	// silences from the config are always loaded, those added through the API are restored until they expire
+	for _, s := range c.Silences {
+		s.fromConfig = true
+		if s.validate() == nil {
+			_ = silences.add(s)
+		}
+	}
+	for _, s := range saved.Silences {
+		if s.End.After(time.Now()) && silences.add(s) == nil {
+			l(slog.LevelInfo, "🔕 restored silence "+s.ID)
+		}
+	}
+
 	// we need to know if the node was already down to clear alarms
 	if saved.NodesDown != nil {
 		for k, v := range saved.NodesDown {
@@ -734,11 +1092,13 @@ func loadConfig(yamlFile, stateFile, chainConfigDirectory string, password *stri
 	return c, nil
 }
 
-func clearStale(alarms map[string]alertMsgCache, what string, hasPagerduty bool, hours float64) {
+// clearStale drops the alarms older than hours from a destination's sent-state, enabled is whether that destination is
+// switched on.
+func clearStale(alarms map[string]alertMsgCache, what string, enabled bool, hours float64) {
 	for k := range alarms {
 		if time.Since(alarms[k].SentTime).Hours() >= hours {
 			l(slog.LevelInfo, fmt.Sprintf("🗑 not restoring old alarm (%v >%.2f hours) from cache - %s", alarms[k], hours, k))
-			if hasPagerduty && what == "pagerduty" {
+			if enabled && what == "pagerduty" {
 				l(slog.LevelWarn, "NOTE: stale alarms may need to be manually cleared from PagerDuty!")
 			}
 			delete(alarms, k)
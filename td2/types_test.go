@@ -408,6 +408,66 @@ func TestApplyAlertDefaultsWithPointerFields(t *testing.T) {
 }
 
 // Helper functions for creating pointers
+func TestApplyAlertDefaultsPushDestinations(t *testing.T) {
+	defaults := &AlertConfig{
+		Matrix: MatrixConfig{
+			Enabled:           boolPtr(true),
+			Homeserver:        "https://matrix.example.org",
+			AccessToken:       "default-token",
+			RoomID:            "!default:example.org",
+			SeverityThreshold: "warning",
+		},
+		Ntfy: NtfyConfig{
+			Enabled: boolPtr(true),
+			Server:  "https://ntfy.example.org",
+			Topic:   "default-topic",
+		},
+		Gotify: GotifyConfig{
+			Enabled: boolPtr(true),
+			Server:  "https://gotify.example.org",
+			Token:   "default-token",
+		},
+	}
+	chain := &AlertConfig{
+		Matrix: MatrixConfig{RoomID: "!chain:example.org"},
+		Ntfy:   NtfyConfig{Enabled: boolPtr(false)},
+		Gotify: GotifyConfig{Token: "chain-token", SeverityThreshold: "critical"},
+	}
+
+	applyAlertDefaults(chain, defaults)
+
+	if chain.Matrix.RoomID != "!chain:example.org" || chain.Matrix.Homeserver != "https://matrix.example.org" ||
+		chain.Matrix.AccessToken != "default-token" || chain.Matrix.SeverityThreshold != "warning" || !boolVal(chain.Matrix.Enabled) {
+		t.Errorf("unexpected matrix config after merge: %+v", chain.Matrix)
+	}
+	if boolVal(chain.Ntfy.Enabled) || chain.Ntfy.Topic != "default-topic" || chain.Ntfy.Server != "https://ntfy.example.org" {
+		t.Errorf("unexpected ntfy config after merge: %+v", chain.Ntfy)
+	}
+	if chain.Gotify.Token != "chain-token" || chain.Gotify.SeverityThreshold != "critical" || chain.Gotify.Server != "https://gotify.example.org" {
+		t.Errorf("unexpected gotify config after merge: %+v", chain.Gotify)
+	}
+}
+
+func TestApplyAlertDefaultsMergesMaps(t *testing.T) {
+	defaults := &AlertConfig{
+		Severities: map[string]string{"StakeChange": "warning", "ChainStalled": "warning"},
+		Pagerduty:  PDConfig{Priorities: map[string]string{"StakeChange": "P3"}},
+	}
+	chain := &AlertConfig{Severities: map[string]string{"StakeChange": "critical"}}
+
+	applyAlertDefaults(chain, defaults)
+
+	if chain.Severities["StakeChange"] != "critical" || chain.Severities["ChainStalled"] != "warning" {
+		t.Errorf("chain severities should be merged with the defaults, got %v", chain.Severities)
+	}
+	if chain.Pagerduty.Priorities["StakeChange"] != "P3" {
+		t.Errorf("unset priorities should come from the defaults, got %v", chain.Pagerduty.Priorities)
+	}
+	if defaults.Severities["StakeChange"] != "warning" {
+		t.Error("the defaults should not be changed")
+	}
+}
+
 func intPtr(i int) *int {
 	return &i
 }
@@ -0,0 +1,190 @@
+package tenderduty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// wsEvent is a reply from one of the websocket subscriptions, with the node it came from and when it arrived.
+type wsEvent struct {
+	*WsReply
+	node     string
+	received time.Time
+}
+
+// wsNodes returns the nodes to subscribe to: the node of the RPC client first, then other healthy nodes until there
+// are websocket_nodes of them.
+func (cc *ChainConfig) wsNodes() []string {
+	want := cc.WebsocketNodes
+	if want <= 0 {
+		want = 2
+	}
+	remote := strings.TrimRight(cc.client.Remote(), "/")
+	nodes := []string{remote}
+	for _, node := range cc.Nodes {
+		if len(nodes) >= want {
+			break
+		}
+		if node.down || node.syncing || strings.TrimRight(node.Url, "/") == remote {
+			continue
+		}
+		nodes = append(nodes, node.Url)
+	}
+	return nodes
+}
+
+// wsSubscriptions are the websocket subscriptions of a chain, they all deliver their events to the same handlers.
+type wsSubscriptions struct {
+	blocks chan *wsEvent
+	votes  chan *wsEvent
+	nodes  int
+
+	mux     sync.Mutex
+	failing map[string]bool
+	// allFailed is called when the last attempt of every subscription failed
+	allFailed func()
+}
+
+// failed records if the last attempt to subscribe to a node failed, allFailed is called when every node's did.
+func (ws *wsSubscriptions) failed(node string, failed bool) {
+	ws.mux.Lock()
+	ws.failing[node] = failed
+	n := 0
+	for _, f := range ws.failing {
+		if f {
+			n += 1
+		}
+	}
+	ws.mux.Unlock()
+	if n == ws.nodes && ws.allFailed != nil {
+		ws.allFailed()
+	}
+}
+
+// subscribe streams the NewBlock and Vote events of a node to the handlers until ctx is done. A failed connection is
+// retried, and the subscriptions to the other nodes keep delivering events meanwhile.
+func (cc *ChainConfig) subscribe(ctx context.Context, node string, ws *wsSubscriptions) {
+	for {
+		err := cc.subscribeOnce(ctx, node, ws)
+		if ctx.Err() != nil {
+			return
+		}
+		l(slog.LevelWarn, fmt.Sprintf("🔌 %-12s websocket to %s failed, retrying: %s", cc.ChainId, node, err))
+		ws.failed(node, true)
+		select {
+		case <-time.After(5 * time.Second):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (cc *ChainConfig) subscribeOnce(ctx context.Context, node string, ws *wsSubscriptions) error {
+	//#nosec G402 -- configurable option
+	conn, err := NewClient(node, td.TLSSkipVerify)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	// closing the connection is the only way to stop a pending read
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+	if err = conn.SetCompressionLevel(3); err != nil {
+		slog.Error("failed to set websocket compression level", "err", err)
+	}
+
+	for _, subscribe := range []string{QueryNewBlock, QueryVote} {
+		q := fmt.Sprintf(`{"jsonrpc":"2.0","method":"subscribe","id":1,"params":{"query":"%s"}}`, subscribe)
+		if err = conn.WriteMessage(websocket.TextMessage, []byte(q)); err != nil {
+			return err
+		}
+	}
+	l(fmt.Sprintf("⚙️ %-12s watching for NewBlock and Vote events via %s", cc.ChainId, node))
+	ws.failed(node, false)
+
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		reply := &WsReply{}
+		if json.Unmarshal(msg, reply) != nil {
+			continue
+		}
+		var events chan *wsEvent
+		switch reply.Type() {
+		case `tendermint/event/NewBlock`:
+			events = ws.blocks
+		case `tendermint/event/Vote`:
+			events = ws.votes
+		default:
+			continue
+		}
+		select {
+		case events <- &wsEvent{WsReply: reply, node: node, received: time.Now()}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// trackedHeights is how many finalized heights the tracker remembers events for, copies that arrive later than this
+// are ignored without measuring their latency.
+const trackedHeights = 10
+
+// eventTracker dedupes the events that arrive from several subscriptions. The first copy of an event is handled, and
+// the latency of each node is how long after the first copy it delivered its own.
+type eventTracker struct {
+	mux    sync.Mutex
+	events map[string]trackedEvent
+	// final is the highest finalized height, events for it or below are late
+	final  int64
+	report func(node string, latency time.Duration)
+}
+
+type trackedEvent struct {
+	height int64
+	first  time.Time
+}
+
+func newEventTracker(report func(node string, latency time.Duration)) *eventTracker {
+	return &eventTracker{events: make(map[string]trackedEvent), report: report}
+}
+
+// first reports if an event is the first copy to arrive. A copy for a height that is already finalized is late, and
+// is not the first even when no other copy was seen.
+func (t *eventTracker) first(key string, height int64, e *wsEvent) bool {
+	t.mux.Lock()
+	seen, dup := t.events[key]
+	late := !dup && height <= t.final
+	if !dup && !late {
+		t.events[key] = trackedEvent{height: height, first: e.received}
+		seen.first = e.received
+	}
+	t.mux.Unlock()
+	if !late && t.report != nil {
+		t.report(e.node, e.received.Sub(seen.first))
+	}
+	return !dup && !late
+}
+
+// finalize marks a height as finalized, and forgets the events of old heights.
+func (t *eventTracker) finalize(height int64) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if height > t.final {
+		t.final = height
+	}
+	for key, e := range t.events {
+		if e.height <= t.final-trackedHeights {
+			delete(t.events, key)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package tenderduty
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+)
+
+func TestEventTracker(t *testing.T) {
+	latencies := make(map[string]time.Duration)
+	tracker := newEventTracker(func(node string, latency time.Duration) { latencies[node] = latency })
+	now := time.Now()
+	event := func(node string, after time.Duration) *wsEvent {
+		return &wsEvent{node: node, received: now.Add(after)}
+	}
+
+	if !tracker.first("vote/10/1", 10, event("a", 0)) {
+		t.Error("the first copy should be handled")
+	}
+	if tracker.first("vote/10/1", 10, event("b", 300*time.Millisecond)) {
+		t.Error("the second copy should be ignored")
+	}
+	if latencies["a"] != 0 || latencies["b"] != 300*time.Millisecond {
+		t.Errorf("unexpected latencies %v", latencies)
+	}
+
+	tracker.finalize(10)
+	if tracker.first("vote/10/2", 10, event("b", time.Second)) {
+		t.Error("a vote for a finalized height is late")
+	}
+	tracker.finalize(30)
+	if len(tracker.events) != 0 {
+		t.Error("old heights should be forgotten")
+	}
+}
+
+func TestWsNodes(t *testing.T) {
+	cc := &ChainConfig{Nodes: []*NodeConfig{
+		{Url: "https://rpc-1.example.com"},
+		{Url: "https://rpc-2.example.com", down: true},
+		{Url: "https://rpc-3.example.com", syncing: true},
+		{Url: "https://rpc-4.example.com/"},
+		{Url: "https://rpc-5.example.com"},
+	}}
+	cc.client, _ = rpchttp.New("https://rpc-4.example.com", "/websocket")
+	expected := []string{"https://rpc-4.example.com", "https://rpc-1.example.com"}
+	if nodes := cc.wsNodes(); fmt.Sprint(nodes) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, nodes)
+	}
+	cc.WebsocketNodes = 5
+	if nodes := cc.wsNodes(); len(nodes) != 3 {
+		t.Errorf("only the healthy nodes should be used, got %v", nodes)
+	}
+}
+
+// newBlockStream serves a websocket that sends a NewBlock event for each height after the subscriptions.
+func newBlockStream(t *testing.T, delay time.Duration, heights ...int64) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for i := 0; i < 2; i++ {
+			if _, _, err = conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+		for _, height := range heights {
+			time.Sleep(delay)
+			msg := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"result":{"query":"tm.event='NewBlock'","data":{"type":"tendermint/event/NewBlock",`+
+				`"value":{"block":{"header":{"height":"%d","proposer_address":"OTHER"},"data":{"txs":[]},"last_commit":{"signatures":[]}}}}}}`, height)
+			if conn.WriteMessage(websocket.TextMessage, []byte(msg)) != nil {
+				return
+			}
+		}
+		// keep the connection open
+		_, _, _ = conn.ReadMessage()
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRedundantSubscriptions(t *testing.T) {
+	setupOutboxTest(t)
+	td.TLSSkipVerify = true
+	cc := td.Chains["test-chain"]
+	fast := newBlockStream(t, 0, 10, 11)
+	slow := newBlockStream(t, 20*time.Millisecond, 10, 11, 12)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mux := sync.Mutex{}
+	reported := make(map[string]int)
+	tracker := newEventTracker(func(node string, latency time.Duration) {
+		mux.Lock()
+		reported[node] += 1
+		mux.Unlock()
+	})
+	results := make(chan StatusUpdate)
+	ws := &wsSubscriptions{blocks: make(chan *wsEvent), votes: make(chan *wsEvent), nodes: 2, failing: make(map[string]bool), allFailed: cancel}
+	go func() { _ = handleBlocks(ctx, ws.blocks, results, "VAL", tracker) }()
+	go cc.subscribe(ctx, fast.URL, ws)
+	go cc.subscribe(ctx, slow.URL, ws)
+
+	heights := make([]int64, 0)
+	timeout := time.After(5 * time.Second)
+	for len(heights) < 3 {
+		select {
+		case upd := <-results:
+			if !upd.Final || upd.Status != Statusmissed {
+				t.Errorf("unexpected update %+v", upd)
+			}
+			heights = append(heights, upd.Height)
+		case <-timeout:
+			t.Fatalf("expected 3 blocks, got %v", heights)
+		}
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	if fmt.Sprint(heights) != "[10 11 12]" {
+		t.Errorf("every height should be handled once, got %v", heights)
+	}
+	select {
+	case upd := <-results:
+		t.Errorf("unexpected duplicate %+v", upd)
+	case <-time.After(100 * time.Millisecond):
+	}
+	mux.Lock()
+	defer mux.Unlock()
+	if reported[fast.URL] < 2 || reported[slow.URL] < 3 {
+		t.Errorf("expected the latency of both nodes, got %v", reported)
+	}
+}
@@ -34,6 +34,8 @@ const (
 	StatusSigned
 	StatusProposed
 	StatusProposedEmpty
+	// StatusMissedProposal is a block the validator was the round 0 proposer for, but another validator proposed
+	StatusMissedProposal
 )
 
 // StatusUpdate is passed over a channel from the websocket client indicating the current state, it is immediate in the
@@ -44,6 +46,10 @@ type StatusUpdate struct {
 	Status StatusType
 	Final  bool
 	Empty  bool
+	// Misbehaviour is the evidence in a block, or the conflicting votes seen from the validator
+	Misbehaviour []misbehaviour
+	// Round is the highest consensus round seen in the votes for the height so far
+	Round int32
 }
 
 // WsReply is a trimmed down version of the JSON sent from a tendermint websocket subscription.
@@ -76,7 +82,6 @@ func (wsr WsReply) Value() []byte {
 func (cc *ChainConfig) WsRun() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	var err error
 	started := time.Now()
 	for {
 		// wait until our RPC client is connected and running. We will use the same URL for the websocket
@@ -92,86 +97,72 @@ func (cc *ChainConfig) WsRun() {
 		break
 	}
 
-	//#nosec G402 -- configurable option
-	cc.wsclient, err = NewClient(cc.client.Remote(), td.TLSSkipVerify)
-	if err != nil {
-		l(slog.LevelError, err)
-		cancel()
-		return
-	}
-	defer cc.wsclient.Close()
-	err = cc.wsclient.SetCompressionLevel(3)
-	if err != nil {
-		slog.Error("failed to set websocket compression level", "err", err)
-	}
+	address := strings.ToUpper(hex.EncodeToString(cc.valInfo.Conspub))
+	// the first copy of every event is handled, whichever node it came from
+	tracker := newEventTracker(func(node string, latency time.Duration) {
+		if td.Prom {
+			td.statsChan <- cc.mkUpdate(metricNodeEventLatency, latency.Seconds(), node)
+		}
+	})
 
 	// This go func processes the results returned by the listeners. It has most of the logic on where data is sent,
 	// like dashboards or prometheus.
 	resultChan := make(chan StatusUpdate)
 	go func() {
-		var signState StatusType = -1
+		// the best status seen for each height that is not final yet, and the highest round
+		best := make(map[int64]StatusType)
+		rounds := make(map[int64]int32)
 		for {
 			select {
 			case update := <-resultChan:
+				if len(update.Misbehaviour) > 0 {
+					cc.alertMisbehaviour(update.Misbehaviour, address)
+				}
 				if update.Final && update.Height%20 == 0 {
 					l(fmt.Sprintf("🧊 %-12s block %d", cc.ChainId, update.Height))
 				}
-				if update.Status > signState && cc.valInfo.Bonded {
-					signState = update.Status
+				if update.Round > 0 {
+					rounds[update.Height] = max(rounds[update.Height], update.Round)
+					continue
+				}
+				if s, ok := best[update.Height]; cc.valInfo.Bonded && (!ok || update.Status > s) {
+					best[update.Height] = update.Status
 				}
 				if update.Final {
+					var signState StatusType = -1
+					if s, ok := best[update.Height]; ok {
+						signState = s
+					}
+					round := rounds[update.Height]
+					for height := range best {
+						if height <= update.Height {
+							delete(best, height)
+						}
+					}
+					for height := range rounds {
+						if height <= update.Height {
+							delete(rounds, height)
+						}
+					}
+					// heights produced while the websocket was reconnecting never arrived, look them up first, new blocks
+					// wait for at most backfillTimeout
+					if cc.lastBlockNum > 0 && update.Height > cc.lastBlockNum+1 {
+						cc.backfill(ctx, cc.lastBlockNum+1, update.Height-1)
+					}
 					cc.lastBlockNum = update.Height
 					if td.Prom {
 						td.statsChan <- cc.mkUpdate(metricLastBlockSeconds, time.Since(cc.lastBlockTime).Seconds(), "")
 					}
 					cc.lastBlockTime = time.Now()
 					info := getAlarms(cc.name)
-					cc.blocksResults = append([]int{int(signState)}, cc.blocksResults[:len(cc.blocksResults)-1]...)
-					excludePreactions := boolVal(cc.Alerts.ConsecutiveMissExcludePreactions)
-					if signState < 3 && cc.valInfo.Bonded {
-						missTypes := []string{"block", "precommit", "prevote"}
-						warn := fmt.Sprintf("❌ warning      %s missed %s %d on %s", cc.valInfo.Moniker, missTypes[signState], update.Height, cc.ChainId)
+					if warn := cc.countBlock(update.Height, signState); warn != "" {
 						info += warn + "\n"
 						cc.lastError = time.Now().UTC().String() + " " + info
-						l(slog.LevelWarn, warn)
 					}
-
-					switch signState {
-					case Statusmissed:
-						cc.statTotalMiss += 1
-						cc.statConsecutiveMiss += 1
-					case StatusPrecommit:
-						cc.statPrecommitMiss += 1
-						cc.statTotalMiss += 1
-						if !excludePreactions {
-							cc.statConsecutiveMiss += 1
-						} else {
-							cc.statConsecutiveMiss = 0
-						}
-					case StatusPrevote:
-						cc.statPrevoteMiss += 1
-						cc.statTotalMiss += 1
-						if !excludePreactions {
-							cc.statConsecutiveMiss += 1
-						} else {
-							cc.statConsecutiveMiss = 0
-						}
-					case StatusSigned:
-						cc.statTotalSigns += 1
-						cc.statConsecutiveMiss = 0
-					case StatusProposed:
-						cc.statTotalProps += 1
-						cc.statTotalSigns += 1
-						cc.statConsecutiveMiss = 0
-						cc.statConsecutiveEmpty = 0
-					case StatusProposedEmpty:
-						cc.statTotalPropsEmpty += 1
-						cc.statTotalProps += 1
-						cc.statTotalSigns += 1
-						cc.statConsecutiveMiss = 0
-						cc.statConsecutiveEmpty += 1
+					if cc.missedProposal(ctx, update.Height, round, signState) {
+						info += cc.countMissedProposal(update.Height, signState) + "\n"
+						cc.lastError = time.Now().UTC().String() + " " + info
 					}
-					signState = -1
 					healthyNodes := 0
 					for i := range cc.Nodes {
 						if !cc.Nodes[i].down {
@@ -203,6 +194,7 @@ func (cc *ChainConfig) WsRun() {
 							Nodes:                   len(cc.Nodes),
 							HealthyNodes:            healthyNodes,
 							ActiveAlerts:            cc.activeAlerts,
+							PendingAlerts:           alarms.pendingCount(cc.name),
 							Height:                  update.Height,
 							LastError:               info,
 							Blocks:                  cc.blocksResults,
@@ -225,15 +217,7 @@ func (cc *ChainConfig) WsRun() {
 					}
 
 					if td.Prom {
-						td.statsChan <- cc.mkUpdate(metricSigned, cc.statTotalSigns, "")
-						td.statsChan <- cc.mkUpdate(metricProposed, cc.statTotalProps, "")
-						td.statsChan <- cc.mkUpdate(metricMissed, cc.statTotalMiss, "")
-						td.statsChan <- cc.mkUpdate(metricPrevote, cc.statPrevoteMiss, "")
-						td.statsChan <- cc.mkUpdate(metricPrecommit, cc.statPrecommitMiss, "")
-						td.statsChan <- cc.mkUpdate(metricConsecutive, cc.statConsecutiveMiss, "")
-						td.statsChan <- cc.mkUpdate(metricEmptyBlocks, float64(cc.statTotalPropsEmpty), "")
-						td.statsChan <- cc.mkUpdate(metricConsecutiveEmpty, float64(cc.statConsecutiveEmpty), "")
-						td.statsChan <- cc.mkUpdate(metricUnealthyNodes, float64(len(cc.Nodes)-healthyNodes), "")
+						cc.blockStats(healthyNodes)
 					}
 				}
 			case <-ctx.Done():
@@ -242,55 +226,25 @@ func (cc *ChainConfig) WsRun() {
 		}
 	}()
 
-	voteChan := make(chan *WsReply)
-	go handleVotes(ctx, voteChan, resultChan, strings.ToUpper(hex.EncodeToString(cc.valInfo.Conspub)))
+	voteChan := make(chan *wsEvent)
+	go handleVotes(ctx, voteChan, resultChan, address, tracker)
 
-	blockChan := make(chan *WsReply)
+	blockChan := make(chan *wsEvent)
 	go func() {
-		e := handleBlocks(ctx, blockChan, resultChan, strings.ToUpper(hex.EncodeToString(cc.valInfo.Conspub)))
+		e := handleBlocks(ctx, blockChan, resultChan, address, tracker)
 		if e != nil {
 			l(slog.LevelError, "🛑", cc.ChainId, e)
 			cancel()
 		}
 	}()
 
-	// now that channel consumers are up, create our subscriptions and route data.
-	go func() {
-		var msg []byte
-		var e error
-		for {
-			_, msg, e = cc.wsclient.ReadMessage()
-			if e != nil {
-				l(slog.LevelError, e)
-				cancel()
-				return
-			}
-			reply := &WsReply{}
-			e = json.Unmarshal(msg, reply)
-			if e != nil {
-				continue
-			}
-			switch reply.Type() {
-			case `tendermint/event/NewBlock`:
-				blockChan <- reply
-			case `tendermint/event/Vote`:
-				voteChan <- reply
-			default:
-				// fmt.Println("unknown response", reply.Type())
-			}
-		}
-	}()
-
-	for _, subscribe := range []string{QueryNewBlock, QueryVote} {
-		q := fmt.Sprintf(`{"jsonrpc":"2.0","method":"subscribe","id":1,"params":{"query":"%s"}}`, subscribe)
-		err = cc.wsclient.WriteMessage(websocket.TextMessage, []byte(q))
-		if err != nil {
-			l(slog.LevelError, err)
-			cancel()
-			break
-		}
+	// now that channel consumers are up, subscribe to the nodes. Events are deduped, so a node that lags or drops
+	// does not leave a gap while another one is connected. When none of them work the client is renegotiated.
+	nodes := cc.wsNodes()
+	ws := &wsSubscriptions{blocks: blockChan, votes: voteChan, nodes: len(nodes), failing: make(map[string]bool), allFailed: cancel}
+	for _, node := range nodes {
+		go cc.subscribe(ctx, node, ws)
 	}
-	l(fmt.Sprintf("⚙️ %-12s watching for NewBlock and Vote events via %s", cc.ChainId, cc.client.Remote()))
 	for {
 		select {
 		case <-cc.client.Quit():
@@ -301,6 +255,71 @@ func (cc *ChainConfig) WsRun() {
 	}
 }
 
+// blockStats sends the block counters to prometheus.
+func (cc *ChainConfig) blockStats(healthyNodes int) {
+	td.statsChan <- cc.mkUpdate(metricSigned, cc.statTotalSigns, "")
+	td.statsChan <- cc.mkUpdate(metricProposed, cc.statTotalProps, "")
+	td.statsChan <- cc.mkUpdate(metricMissed, cc.statTotalMiss, "")
+	td.statsChan <- cc.mkUpdate(metricPrevote, cc.statPrevoteMiss, "")
+	td.statsChan <- cc.mkUpdate(metricPrecommit, cc.statPrecommitMiss, "")
+	td.statsChan <- cc.mkUpdate(metricConsecutive, cc.statConsecutiveMiss, "")
+	td.statsChan <- cc.mkUpdate(metricEmptyBlocks, float64(cc.statTotalPropsEmpty), "")
+	td.statsChan <- cc.mkUpdate(metricConsecutiveEmpty, float64(cc.statConsecutiveEmpty), "")
+	td.statsChan <- cc.mkUpdate(metricMissedProposals, cc.statTotalMissedProps, "")
+	td.statsChan <- cc.mkUpdate(metricUnealthyNodes, float64(len(cc.Nodes)-healthyNodes), "")
+}
+
+// countBlock adds the final status of a block to the dashboard's grid and to the counters. It returns a warning when
+// the validator missed the block.
+func (cc *ChainConfig) countBlock(height int64, signState StatusType) (warn string) {
+	cc.blocksResults = append([]int{int(signState)}, cc.blocksResults[:len(cc.blocksResults)-1]...)
+	excludePreactions := boolVal(cc.Alerts.ConsecutiveMissExcludePreactions)
+	if signState >= Statusmissed && signState < 3 && cc.valInfo.Bonded {
+		missTypes := []string{"block", "precommit", "prevote"}
+		warn = fmt.Sprintf("❌ warning      %s missed %s %d on %s", cc.valInfo.Moniker, missTypes[signState], height, cc.ChainId)
+		l(slog.LevelWarn, warn)
+	}
+
+	switch signState {
+	case Statusmissed:
+		cc.statTotalMiss += 1
+		cc.statConsecutiveMiss += 1
+	case StatusPrecommit:
+		cc.statPrecommitMiss += 1
+		cc.statTotalMiss += 1
+		if !excludePreactions {
+			cc.statConsecutiveMiss += 1
+		} else {
+			cc.statConsecutiveMiss = 0
+		}
+	case StatusPrevote:
+		cc.statPrevoteMiss += 1
+		cc.statTotalMiss += 1
+		if !excludePreactions {
+			cc.statConsecutiveMiss += 1
+		} else {
+			cc.statConsecutiveMiss = 0
+		}
+	case StatusSigned:
+		cc.statTotalSigns += 1
+		cc.statConsecutiveMiss = 0
+	case StatusProposed:
+		cc.statTotalProps += 1
+		cc.statTotalSigns += 1
+		cc.statConsecutiveMiss = 0
+		cc.statConsecutiveEmpty = 0
+		cc.statConsecutiveMissedProps = 0
+	case StatusProposedEmpty:
+		cc.statTotalPropsEmpty += 1
+		cc.statTotalProps += 1
+		cc.statTotalSigns += 1
+		cc.statConsecutiveMiss = 0
+		cc.statConsecutiveEmpty += 1
+		cc.statConsecutiveMissedProps = 0
+	}
+	return
+}
+
 type stringInt64 string
 
 // helper to make the "everything is a string" issue less painful.
@@ -326,6 +345,9 @@ type rawBlock struct {
 		Data struct {
 			Txs []json.RawMessage `json:"txs"`
 		} `json:"data"`
+		Evidence struct {
+			Evidence []rawEvidence `json:"evidence"`
+		} `json:"evidence"`
 	} `json:"block"`
 }
 
@@ -342,43 +364,52 @@ func (rb rawBlock) find(val string) bool {
 	return false
 }
 
+// status classifies a finalized block for a validator: proposed, possibly empty, signed or missed.
+func (rb rawBlock) status(val string) StatusType {
+	switch {
+	case rb.Block.Header.ProposerAddress == val && len(rb.Block.Data.Txs) == 0:
+		return StatusProposedEmpty
+	case rb.Block.Header.ProposerAddress == val:
+		return StatusProposed
+	case rb.find(val):
+		return StatusSigned
+	}
+	return Statusmissed
+}
+
 // handleBlocks consumes the channel for new blocks and when it sees one sends a status update. It's also
-// responsible for stalled chain detection and will shutdown the client if there are no blocks for a minute.
-func handleBlocks(ctx context.Context, blocks chan *WsReply, results chan StatusUpdate, address string) error {
+// responsible for stalled chain detection and will shutdown the client if there are no new blocks for a minute.
+func handleBlocks(ctx context.Context, blocks chan *wsEvent, results chan StatusUpdate, address string, tracker *eventTracker) error {
 	live := time.NewTicker(time.Minute)
 	defer live.Stop()
 	lastBlock := time.Now()
 	for {
 		select {
 		case <-live.C:
-			// no block for a full minute likely means we have either a dead chain, or a dead client.
+			// no block for a full minute likely means we have either a dead chain, or dead clients.
 			if lastBlock.Before(time.Now().Add(-time.Minute)) {
 				return errors.New("websocket idle for 1 minute, exiting")
 			}
 		case block := <-blocks:
-			lastBlock = time.Now()
 			b := &rawBlock{}
 			err := json.Unmarshal(block.Value(), b)
 			if err != nil {
 				l(slog.LevelError, "could not decode block", err)
 				continue
 			}
-			upd := StatusUpdate{
-				Height: b.Block.Header.Height.val(),
-				Status: Statusmissed,
-				Final:  true,
-				Empty:  len(b.Block.Data.Txs) == 0,
+			height := b.Block.Header.Height.val()
+			if !tracker.first(fmt.Sprintf("block/%d", height), height, block) {
+				continue
 			}
-			if b.Block.Header.ProposerAddress == address {
-				if upd.Empty {
-					upd.Status = StatusProposedEmpty
-				} else {
-					upd.Status = StatusProposed
-				}
-			} else if b.find(address) {
-				upd.Status = StatusSigned
+			lastBlock = time.Now()
+			tracker.finalize(height)
+			results <- StatusUpdate{
+				Height:       height,
+				Status:       b.status(address),
+				Final:        true,
+				Empty:        len(b.Block.Data.Txs) == 0,
+				Misbehaviour: b.evidence(),
 			}
-			results <- upd
 		case <-ctx.Done():
 			return nil
 		}
@@ -388,14 +419,21 @@ func handleBlocks(ctx context.Context, blocks chan *WsReply, results chan Status
 // rawVote is a trimmed down version of the vote response.
 type rawVote struct {
 	Vote struct {
-		Type             pbtypes.SignedMsgType `json:"type"`
-		Height           stringInt64           `json:"height"`
-		ValidatorAddress string                `json:"validator_address"`
+		Type    pbtypes.SignedMsgType `json:"type"`
+		Height  stringInt64           `json:"height"`
+		Round   int32                 `json:"round"`
+		BlockID struct {
+			Hash string `json:"hash"`
+		} `json:"block_id"`
+		ValidatorAddress string `json:"validator_address"`
 	} `json:"Vote"`
 }
 
-// handleVotes consumes the channel for precommits and prevotes, tracking where in the process a validator is.
-func handleVotes(ctx context.Context, votes chan *WsReply, results chan StatusUpdate, address string) {
+// handleVotes consumes the channel for precommits and prevotes, tracking where in the process a validator is. It
+// also watches for two conflicting votes from the validator, and for heights that went past round 0.
+func handleVotes(ctx context.Context, votes chan *wsEvent, results chan StatusUpdate, address string, tracker *eventTracker) {
+	conflicts := make(voteConflicts)
+	rounds := make(voteRounds)
 	for {
 		select {
 		case reply := <-votes:
@@ -405,6 +443,9 @@ func handleVotes(ctx context.Context, votes chan *WsReply, results chan StatusUp
 				l(slog.LevelError, err)
 				continue
 			}
+			if rounds.saw(vote.Vote.Height.val(), vote.Vote.Round) {
+				results <- StatusUpdate{Height: vote.Vote.Height.val(), Status: -1, Round: vote.Vote.Round}
+			}
 			if vote.Vote.ValidatorAddress == address {
 				upd := StatusUpdate{Height: vote.Vote.Height.val()}
 				switch vote.Vote.Type.String() {
@@ -417,6 +458,14 @@ func handleVotes(ctx context.Context, votes chan *WsReply, results chan StatusUp
 				case "SIGNED_MSG_TYPE_PROPOSAL":
 					upd.Status = StatusProposed
 				}
+				if conflicts.conflicts(vote) {
+					upd.Misbehaviour = []misbehaviour{{kind: "ConflictingVotes", height: upd.Height, validators: []string{address}}}
+					results <- upd
+					continue
+				}
+				if !tracker.first(fmt.Sprintf("vote/%d/%d", upd.Height, upd.Status), upd.Height, reply) {
+					continue
+				}
 				results <- upd
 			}
 